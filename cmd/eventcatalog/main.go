@@ -0,0 +1,241 @@
+// Command eventcatalog writes a Markdown catalog of the WebSocket protocol
+// from the source of the events package: every constant group and every
+// payload or message type, with their doc comments.
+//
+// It is run by go generate in internal/events.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the events package")
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	catalog, err := generate(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(catalog)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, catalog, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate renders the catalog for the package in dir. Declarations appear
+// in source order, with files sorted by name.
+func generate(dir string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	names := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var consts []*ast.GenDecl
+	var typeSpecs []*ast.TypeSpec
+	typeDocs := make(map[string]string)
+	var pkgDoc string
+	for _, name := range names {
+		file := pkg.Files[name]
+		if file.Doc != nil {
+			pkgDoc = file.Doc.Text()
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch gen.Tok {
+			case token.CONST:
+				consts = append(consts, gen)
+			case token.TYPE:
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if !ts.Name.IsExported() {
+						continue
+					}
+					typeSpecs = append(typeSpecs, ts)
+					typeDocs[ts.Name.Name] = docText(ts.Doc, gen.Doc)
+				}
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "# WebSocket events")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "<!-- Code generated by cmd/eventcatalog from internal/events. DO NOT EDIT. -->")
+	fmt.Fprintln(&b)
+	if pkgDoc != "" {
+		fmt.Fprintln(&b, strings.TrimSpace(pkgDoc))
+		fmt.Fprintln(&b)
+	}
+
+	fmt.Fprintln(&b, "## Constants")
+	for _, gen := range consts {
+		writeConsts(&b, gen, typeDocs)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "## Types")
+	for _, ts := range typeSpecs {
+		writeType(&b, ts, typeDocs[ts.Name.Name])
+	}
+	return b.Bytes(), nil
+}
+
+// writeConsts renders a const block as a table. Constants naming a message
+// type link to the payload and message types that share their name.
+func writeConsts(b *bytes.Buffer, gen *ast.GenDecl, typeDocs map[string]string) {
+	fmt.Fprintln(b)
+	if doc := gen.Doc.Text(); doc != "" {
+		fmt.Fprintln(b, oneParagraph(doc))
+		fmt.Fprintln(b)
+	}
+	fmt.Fprintln(b, "| Name | Value | Types | Description |")
+	fmt.Fprintln(b, "| --- | --- | --- | --- |")
+	for _, spec := range gen.Specs {
+		vs := spec.(*ast.ValueSpec)
+		for i, name := range vs.Names {
+			if !name.IsExported() {
+				continue
+			}
+			value := ""
+			if i < len(vs.Values) {
+				value = "`" + types.ExprString(vs.Values[i]) + "`"
+			}
+			var linked []string
+			for _, suffix := range []string{"Payload", "Message"} {
+				if _, ok := typeDocs[name.Name+suffix]; ok {
+					linked = append(linked, typeLink(name.Name+suffix))
+				}
+			}
+			fmt.Fprintf(b, "| `%s` | %s | %s | %s |\n", name.Name, value, strings.Join(linked, ", "), cell(docText(vs.Doc, vs.Comment)))
+		}
+	}
+}
+
+// writeType renders a type with a table of the fields sent on the wire.
+func writeType(b *bytes.Buffer, ts *ast.TypeSpec, doc string) {
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "### %s\n", ts.Name.Name)
+	fmt.Fprintln(b)
+	if doc != "" {
+		fmt.Fprintln(b, oneParagraph(doc))
+		fmt.Fprintln(b)
+	}
+
+	st, ok := ts.Type.(*ast.StructType)
+	if !ok {
+		fmt.Fprintf(b, "Type: `%s`\n", types.ExprString(ts.Type))
+		return
+	}
+	fmt.Fprintln(b, "| Field | Type | Description |")
+	fmt.Fprintln(b, "| --- | --- | --- |")
+	for _, field := range st.Fields.List {
+		for _, name := range fieldNames(field) {
+			jsonName, omitEmpty, ok := jsonField(field, name)
+			if !ok {
+				continue
+			}
+			if omitEmpty {
+				jsonName += " (optional)"
+			}
+			fmt.Fprintf(b, "| `%s` | `%s` | %s |\n", jsonName, types.ExprString(field.Type), cell(docText(field.Doc, field.Comment)))
+		}
+	}
+}
+
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		return []string{types.ExprString(field.Type)}
+	}
+	names := make([]string, 0, len(field.Names))
+	for _, name := range field.Names {
+		if name.IsExported() {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// jsonField returns the name a field is encoded under, following the rules
+// of encoding/json. ok is false for fields that are never encoded.
+func jsonField(field *ast.Field, name string) (jsonName string, omitEmpty, ok bool) {
+	tag := ""
+	if field.Tag != nil {
+		tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+	}
+	if tag == "-" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	jsonName = name
+	if parts[0] != "" {
+		jsonName = parts[0]
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return jsonName, omitEmpty, true
+}
+
+// docText returns the first non-empty comment group.
+func docText(groups ...*ast.CommentGroup) string {
+	for _, group := range groups {
+		if text := strings.TrimSpace(group.Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+func oneParagraph(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// cell escapes text for a Markdown table cell.
+func cell(text string) string {
+	return strings.ReplaceAll(oneParagraph(text), "|", `\|`)
+}
+
+func typeLink(name string) string {
+	return fmt.Sprintf("[%s](#%s)", name, strings.ToLower(name))
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCatalogIsUpToDate fails when the events package changed without
+// regenerating the catalog. Run go generate ./internal/events to fix it.
+func TestCatalogIsUpToDate(t *testing.T) {
+	want, err := generate("../../internal/events")
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../docs/events.md")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
 	"github.com/Ajstraight619/pictionary-server/internal/config"
 	"github.com/Ajstraight619/pictionary-server/internal/db"
 	"github.com/Ajstraight619/pictionary-server/internal/handlers"
@@ -18,21 +21,34 @@ import (
 
 func main() {
 	cfg := config.GetConfig()
-	gameServer := server.NewGameServer()
+
+	var blocklist *chat.Blocklist
+	if cfg.ChatBlocklistPath != "" {
+		var err error
+		if blocklist, err = chat.LoadBlocklist(cfg.ChatBlocklistPath); err != nil {
+			log.Fatalf("Failed to load chat blocklist: %v", err)
+		}
+		log.Printf("Loaded %d blocked chat words", blocklist.Len())
+	}
+	gameServer := server.NewGameServer(blocklist)
 
 	e := echo.New()
+	e.IPExtractor = ipExtractor(cfg.TrustedProxies)
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
 	db.InitDB("data/game.db")
+	if err := db.MigrateWords(); err != nil {
+		log.Fatalf("Failed to migrate words: %v", err)
+	}
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: cfg.AllowedOrigins,
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions},
-		AllowHeaders: []string{"Origin", "Content-Type", "Accept"},
+		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions},
+		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"},
 	}))
 
-	handlers.RegisterRoutes(e, gameServer)
+	handlers.RegisterRoutes(e, gameServer, cfg)
 
 	go func() {
 		quit := make(chan os.Signal, 1)
@@ -53,3 +69,25 @@ func main() {
 
 	e.Logger.Fatal(e.Start(":" + cfg.Port))
 }
+
+// ipExtractor returns how client IPs, which bans are keyed on, are found.
+// X-Forwarded-For is only believed when it was set by one of the trusted
+// proxies, since clients can send it themselves.
+func ipExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatalf("Invalid trusted proxy range %q: %v", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
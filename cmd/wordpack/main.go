@@ -0,0 +1,164 @@
+// Command wordpack imports word packs into the word bank and exports them
+// again, as JSON or CSV.
+//
+//	wordpack import [-db path] [-name name] [-version version] [-format json|csv] file
+//	wordpack export [-db path] -name name [-format json|csv] [-o file]
+//
+// Importing is idempotent: words are added or updated in place, and words an
+// earlier import of the pack added but the file no longer lists are removed
+// unless another pack holds them too.
+//
+// A running server keeps the word bank cached in memory and does not see
+// changes this command writes to its database until it is restarted. To update
+// the word bank of a live server, use the admin API (PUT /admin/packs/{name})
+// instead.
+//
+// The bundled word list is seeded with
+//
+//	go run ./cmd/wordpack import -name default internal/db/words.json
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/Ajstraight619/pictionary-server/internal/db"
+	"github.com/Ajstraight619/pictionary-server/internal/wordpack"
+)
+
+const defaultDatabasePath = "data/game.db"
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: wordpack import [-db path] [-name name] [-version version] [-format json|csv] file")
+	fmt.Fprintln(os.Stderr, "       wordpack export [-db path] -name name [-format json|csv] [-o file]")
+	os.Exit(2)
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	databasePath := flags.String("db", defaultDatabasePath, "database file")
+	name := flags.String("name", "", "pack name (default the name in the file)")
+	version := flags.String("version", "", "pack version (default the version in the file)")
+	format := flags.String("format", "", "file format, json or csv (default from the file extension)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+	path := flags.Arg(0)
+
+	f, err := fileFormat(*format, path)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	pack, err := wordpack.Decode(file, f)
+	if err != nil {
+		return err
+	}
+	if *name != "" {
+		pack.Name = *name
+	}
+	if *version != "" {
+		pack.Version = *version
+	}
+	if pack.Name == "" {
+		return errors.New("the file does not name its pack, so -name is required")
+	}
+
+	if err := openDatabase(*databasePath); err != nil {
+		return err
+	}
+	result, err := db.ImportPack(pack)
+	if err != nil {
+		return err
+	}
+	log.Printf("Imported pack %q: %d added, %d updated, %d unchanged, %d removed",
+		result.Pack.Name, result.Added, result.Updated, result.Unchanged, result.Removed)
+	log.Println("Restart any server using this database to pick up the changes.")
+	return nil
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	databasePath := flags.String("db", defaultDatabasePath, "database file")
+	name := flags.String("name", "", "pack name")
+	format := flags.String("format", "", "file format, json or csv (default from the output file extension, or json)")
+	out := flags.String("o", "", "output file (default stdout)")
+	flags.Parse(args)
+	if *name == "" || flags.NArg() != 0 {
+		usage()
+	}
+
+	f := wordpack.FormatJSON
+	if *format != "" || *out != "" {
+		var err error
+		if f, err = fileFormat(*format, *out); err != nil {
+			return err
+		}
+	}
+
+	if err := openDatabase(*databasePath); err != nil {
+		return err
+	}
+	pack, err := db.ExportPack(*name)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	return wordpack.Encode(w, f, pack)
+}
+
+// fileFormat returns the format named by the -format flag, falling back to
+// the extension of path.
+func fileFormat(flagValue, path string) (wordpack.Format, error) {
+	if flagValue != "" {
+		return wordpack.ParseFormat(flagValue)
+	}
+	return wordpack.FormatOf(path)
+}
+
+func openDatabase(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	db.InitDB(path)
+	return db.MigrateWords()
+}
@@ -0,0 +1,131 @@
+package chat
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Blocklist masks blocked words and phrases in chat messages. A nil or empty
+// Blocklist lets everything through.
+type Blocklist struct {
+	// phrases holds the blocked entries, split into words, by their first
+	// word.
+	phrases map[string][][]string
+	len     int
+}
+
+// NewBlocklist builds a blocklist from words, which may be phrases of several
+// words. Matching ignores case and the punctuation and spacing between words.
+func NewBlocklist(words ...string) *Blocklist {
+	b := &Blocklist{phrases: make(map[string][][]string, len(words))}
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		phrase := strings.FieldsFunc(strings.ToLower(word), func(r rune) bool { return !isWordRune(r) })
+		key := strings.Join(phrase, " ")
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		b.phrases[phrase[0]] = append(b.phrases[phrase[0]], phrase)
+		b.len++
+	}
+	return b
+}
+
+// LoadBlocklist reads one word or phrase per line from path. Blank lines and lines
+// starting with # are skipped.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewBlocklist(words...), nil
+}
+
+// Len returns how many words and phrases are blocked.
+func (b *Blocklist) Len() int {
+	if b == nil {
+		return 0
+	}
+	return b.len
+}
+
+// token is a word in a chat message, between runes start and end.
+type token struct {
+	start, end int
+	word       string
+}
+
+// Filter replaces every blocked word or phrase in text with asterisks. Only
+// whole words are matched, so blocking "ass" leaves "class" alone.
+func (b *Blocklist) Filter(text string) string {
+	if b.Len() == 0 {
+		return text
+	}
+	runes := []rune(text)
+	var tokens []token
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		tokens = append(tokens, token{start, end, strings.ToLower(string(runes[start:end]))})
+		start = end
+	}
+
+	for i, tok := range tokens {
+		for _, phrase := range b.phrases[tok.word] {
+			if !matches(tokens[i:], phrase) {
+				continue
+			}
+			for _, t := range tokens[i : i+len(phrase)] {
+				for j := t.start; j < t.end; j++ {
+					runes[j] = '*'
+				}
+			}
+		}
+	}
+	return string(runes)
+}
+
+// matches reports whether tokens start with phrase.
+func matches(tokens []token, phrase []string) bool {
+	if len(tokens) < len(phrase) {
+		return false
+	}
+	for i, word := range phrase {
+		if tokens[i].word != word {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether text has a blocked word in it.
+func (b *Blocklist) Contains(text string) bool {
+	return b.Filter(text) != text
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,54 @@
+package chat_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklistFilter(t *testing.T) {
+	blocklist := chat.NewBlocklist("darn", "Heck", " ")
+
+	assert.Equal(t, 2, blocklist.Len())
+	assert.Equal(t, "oh **** it", blocklist.Filter("oh darn it"))
+	assert.Equal(t, "****! ****, ****", blocklist.Filter("HECK! Darn, darn"))
+	assert.Equal(t, "darning heckler", blocklist.Filter("darning heckler"))
+	assert.Equal(t, "", blocklist.Filter(""))
+	assert.True(t, blocklist.Contains("well darn"))
+	assert.False(t, blocklist.Contains("darning heckler"))
+}
+
+func TestNilBlocklistFiltersNothing(t *testing.T) {
+	var blocklist *chat.Blocklist
+	assert.Zero(t, blocklist.Len())
+	assert.Equal(t, "oh darn", blocklist.Filter("oh darn"))
+	assert.False(t, blocklist.Contains("oh darn"))
+}
+
+func TestLoadBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\ndarn\n\n  heck  \n"), 0o600))
+
+	blocklist, err := chat.LoadBlocklist(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, blocklist.Len())
+	assert.Equal(t, "**** and ****", blocklist.Filter("darn and heck"))
+
+	_, err = chat.LoadBlocklist(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestBlocklistPhrases(t *testing.T) {
+	blocklist := chat.NewBlocklist("son of a gun", "Son  of a GUN", "heck")
+
+	assert.Equal(t, 2, blocklist.Len())
+	assert.Equal(t, "you *** ** * ***!", blocklist.Filter("you son of a gun!"))
+	assert.Equal(t, "***, ** *   ***", blocklist.Filter("SON, of a   gun"))
+	assert.Equal(t, "son of a gunner", blocklist.Filter("son of a gunner"))
+	assert.Equal(t, "son of a", blocklist.Filter("son of a"))
+	assert.Equal(t, "****", blocklist.Filter("heck"))
+}
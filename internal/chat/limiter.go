@@ -0,0 +1,49 @@
+package chat
+
+import "time"
+
+// Limiter is a per-key token bucket. Each key may send burst messages at
+// once and earns another one every interval. It is not safe for concurrent
+// use.
+type Limiter struct {
+	burst    int
+	interval time.Duration
+	buckets  map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(burst int, interval time.Duration) *Limiter {
+	return &Limiter{
+		burst:    burst,
+		interval: interval,
+		buckets:  make(map[string]*bucket),
+	}
+}
+
+// Allow reports whether key may send a message at now, and if so spends a
+// token.
+func (l *Limiter) Allow(key string, now time.Time) bool {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(l.burst), b.tokens+float64(elapsed)/float64(l.interval))
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Forget drops the state kept for key.
+func (l *Limiter) Forget(key string) {
+	delete(l.buckets, key)
+}
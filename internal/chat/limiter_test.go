@@ -0,0 +1,33 @@
+package chat_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := chat.NewLimiter(2, time.Second)
+	now := time.Unix(0, 0)
+
+	assert.True(t, limiter.Allow("a", now))
+	assert.True(t, limiter.Allow("a", now))
+	assert.False(t, limiter.Allow("a", now))
+
+	// Keys have their own buckets.
+	assert.True(t, limiter.Allow("b", now))
+
+	// Tokens come back over time, up to the burst.
+	assert.False(t, limiter.Allow("a", now.Add(500*time.Millisecond)))
+	assert.True(t, limiter.Allow("a", now.Add(time.Second)))
+	assert.False(t, limiter.Allow("a", now.Add(time.Second)))
+	later := now.Add(time.Hour)
+	assert.True(t, limiter.Allow("a", later))
+	assert.True(t, limiter.Allow("a", later))
+	assert.False(t, limiter.Allow("a", later))
+
+	limiter.Forget("a")
+	assert.True(t, limiter.Allow("a", later))
+}
@@ -1,22 +1,48 @@
 package config
 
-import "os"
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+	"strings"
+)
 
 type Config struct {
 	Port           string
 	Environment    string
 	AllowedOrigins []string
+	SessionSecret  string
+	// ChatBlocklistPath is a file listing words masked in chat, one per
+	// line. Chat is not filtered when it is empty.
+	ChatBlocklistPath string
+	// AdminToken is the bearer token the word bank admin API requires. The
+	// admin API is disabled when it is empty.
+	AdminToken string
+	// TrustedProxies lists the IP ranges, in CIDR notation, of the proxies in
+	// front of the server. Client IPs are read from X-Forwarded-For only when
+	// the request comes through one of them, and from the connection
+	// otherwise.
+	TrustedProxies []string
 }
 
 func GetConfig() *Config {
 	if os.Getenv("RAILWAY_ENVIRONMENT_NAME") != "" {
 		// Production settings
+		secret := os.Getenv("SESSION_SECRET")
+		if secret == "" {
+			log.Fatal("SESSION_SECRET must be set in production")
+		}
 		return &Config{
 			Port:        os.Getenv("PORT"),
 			Environment: "production",
 			AllowedOrigins: []string{
 				"",
 			},
+			SessionSecret:     secret,
+			ChatBlocklistPath: os.Getenv("CHAT_BLOCKLIST_PATH"),
+			AdminToken:        os.Getenv("ADMIN_TOKEN"),
+			TrustedProxies:    trustedProxies(),
 		}
 	}
 
@@ -28,5 +54,33 @@ func GetConfig() *Config {
 			"http://localhost:5173",
 			"http://127.0.0.1:5173",
 		},
+		SessionSecret:     devSessionSecret(),
+		ChatBlocklistPath: os.Getenv("CHAT_BLOCKLIST_PATH"),
+		AdminToken:        os.Getenv("ADMIN_TOKEN"),
+		TrustedProxies:    trustedProxies(),
+	}
+}
+
+// trustedProxies reads the comma separated TRUSTED_PROXIES variable.
+func trustedProxies() []string {
+	var proxies []string
+	for _, cidr := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			proxies = append(proxies, cidr)
+		}
+	}
+	return proxies
+}
+
+// devSessionSecret falls back to a per-process secret, so tokens issued in
+// development stop working after a restart along with the games they belong to.
+func devSessionSecret() string {
+	if secret := os.Getenv("SESSION_SECRET"); secret != "" {
+		return secret
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to generate session secret: %v", err)
 	}
+	return hex.EncodeToString(b)
 }
@@ -11,13 +11,15 @@ var DB *gorm.DB
 func InitDB(dsn string) {
 	var err error
 
-	DB, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	// TranslateError turns constraint violations into gorm errors, such as
+	// gorm.ErrDuplicatedKey, that callers can match on.
+	DB, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
 	log.Println("Database connection initialized")
-
+	InvalidateWordIndex()
 }
 
 func MigrateModels(models ...interface{}) {
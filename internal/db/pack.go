@@ -0,0 +1,399 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/Ajstraight619/pictionary-server/internal/wordpack"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPackNotFound  = errors.New("word pack not found")
+	ErrWordNotFound  = errors.New("word not found")
+	ErrDuplicateWord = errors.New("word already exists in this category")
+)
+
+// ImportResult counts what ImportPack changed.
+type ImportResult struct {
+	Pack      shared.WordPack `json:"pack"`
+	Added     int             `json:"added"`
+	Updated   int             `json:"updated"`
+	Unchanged int             `json:"unchanged"`
+	// Removed counts words of an earlier import of the pack that are not in
+	// this one. They stay in the word bank while another pack holds them.
+	Removed int `json:"removed"`
+}
+
+// PackSummary describes a word pack and how many words it holds.
+type PackSummary struct {
+	shared.WordPack
+	Words    int `json:"words"`
+	Disabled int `json:"disabled"`
+}
+
+// WordQuery selects the words ListWords returns. Zero fields match every
+// word.
+type WordQuery struct {
+	Category string
+	Pack     string
+	// Search matches words containing it, ignoring case.
+	Search string
+	Limit  int
+	Offset int
+}
+
+// ImportPack adds the words of pack to the word bank, or updates them if they
+// are already there, and removes words the pack no longer contains. Words are
+// matched by word and category, ignoring case, so importing the same pack
+// again changes nothing. A word already in the bank joins the pack, but is
+// left as it is if another pack holds it too.
+func ImportPack(pack wordpack.Pack) (ImportResult, error) {
+	if err := pack.Normalize(); err != nil {
+		return ImportResult{}, err
+	}
+
+	var result ImportResult
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		record := shared.WordPack{Name: pack.Name}
+		if err := tx.Where("name = ?", pack.Name).FirstOrCreate(&record).Error; err != nil {
+			return err
+		}
+		record.Version = pack.Version
+		record.ImportedAt = time.Now()
+		if err := tx.Save(&record).Error; err != nil {
+			return err
+		}
+		result.Pack = record
+
+		kept := make([]uint64, 0, len(pack.Words))
+		for _, entry := range pack.Words {
+			id, change, err := upsertWord(tx, record.Id, entry)
+			if err != nil {
+				return fmt.Errorf("importing %q: %w", entry.Word, err)
+			}
+			kept = append(kept, id)
+			switch change {
+			case wordAdded:
+				result.Added++
+			case wordUpdated:
+				result.Updated++
+			default:
+				result.Unchanged++
+			}
+		}
+
+		var stale []uint64
+		members := tx.Model(&shared.PackWord{}).Where("pack_id = ?", record.Id)
+		if len(kept) > 0 {
+			members = members.Where("word_id NOT IN ?", kept)
+		}
+		if err := members.Pluck("word_id", &stale).Error; err != nil {
+			return err
+		}
+		result.Removed = len(stale)
+		_, err := removeFromPack(tx, record.Id, stale)
+		return err
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	InvalidateWordIndex()
+	return result, nil
+}
+
+type wordChange int
+
+const (
+	wordUnchanged wordChange = iota
+	wordAdded
+	wordUpdated
+)
+
+// upsertWord stores entry as part of the pack with ID packID. A word that is
+// already in the bank keeps its ID and whether it is disabled, and is only
+// updated if no other pack holds it.
+func upsertWord(tx *gorm.DB, packID uint, entry wordpack.Entry) (uint64, wordChange, error) {
+	existing, found, err := findWord(tx, entry.Word, entry.Category)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		word := shared.Word{
+			Word:       entry.Word,
+			Category:   entry.Category,
+			Difficulty: entry.Difficulty,
+			Aliases:    entry.Aliases,
+			Disabled:   entry.Disabled,
+		}
+		if err := tx.Create(&word).Error; err != nil {
+			return 0, 0, err
+		}
+		if _, err := addToPack(tx, packID, word.Id); err != nil {
+			return 0, 0, err
+		}
+		return word.Id, wordAdded, nil
+	}
+
+	joined, err := addToPack(tx, packID, existing.Id)
+	if err != nil {
+		return 0, 0, err
+	}
+	change := wordUnchanged
+	if joined {
+		change = wordUpdated
+	}
+
+	var others int64
+	err = tx.Model(&shared.PackWord{}).Where("word_id = ? AND pack_id <> ?", existing.Id, packID).Count(&others).Error
+	if err != nil || others > 0 {
+		return existing.Id, change, err
+	}
+	if existing.Word == entry.Word &&
+		existing.Difficulty == entry.Difficulty &&
+		slices.Equal(existing.Aliases, entry.Aliases) {
+		return existing.Id, change, nil
+	}
+	err = tx.Model(&existing).Select("word", "difficulty", "aliases").Updates(shared.Word{
+		Word:       entry.Word,
+		Difficulty: entry.Difficulty,
+		Aliases:    entry.Aliases,
+	}).Error
+	return existing.Id, wordUpdated, err
+}
+
+// addToPack adds the word with ID wordID to the pack with ID packID. It
+// reports whether the word was not in the pack yet.
+func addToPack(tx *gorm.DB, packID uint, wordID uint64) (bool, error) {
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&shared.PackWord{PackID: packID, WordID: wordID})
+	return res.RowsAffected > 0, res.Error
+}
+
+// removeFromPack takes the words with the given IDs out of the pack with ID
+// packID, and deletes those that no other pack holds. It returns how many
+// words were deleted.
+func removeFromPack(tx *gorm.DB, packID uint, wordIDs []uint64) (int, error) {
+	if len(wordIDs) == 0 {
+		return 0, nil
+	}
+	err := tx.Where("pack_id = ? AND word_id IN ?", packID, wordIDs).Delete(&shared.PackWord{}).Error
+	if err != nil {
+		return 0, err
+	}
+	res := tx.Where("id IN ? AND id NOT IN (?)", wordIDs, DB.Model(&shared.PackWord{}).Select("word_id")).
+		Delete(&shared.Word{})
+	return int(res.RowsAffected), res.Error
+}
+
+// packWords selects the words in the pack with ID packID.
+func packWords(tx *gorm.DB, packID uint) *gorm.DB {
+	return tx.Where("id IN (?)", DB.Model(&shared.PackWord{}).Select("word_id").Where("pack_id = ?", packID))
+}
+
+// findWord looks a word up by its text, ignoring case, and category.
+func findWord(tx *gorm.DB, word, category string) (shared.Word, bool, error) {
+	var found shared.Word
+	res := tx.Where("word = ? COLLATE NOCASE AND category = ?", word, category).Limit(1).Find(&found)
+	return found, res.RowsAffected > 0, res.Error
+}
+
+// ExportPack returns the pack named name with its words, sorted by category
+// and word.
+func ExportPack(name string) (wordpack.Pack, error) {
+	record, err := findPack(name)
+	if err != nil {
+		return wordpack.Pack{}, err
+	}
+	var words []shared.Word
+	if err := packWords(DB, record.Id).Order("category, word COLLATE NOCASE").Find(&words).Error; err != nil {
+		return wordpack.Pack{}, err
+	}
+
+	pack := wordpack.Pack{
+		Name:    record.Name,
+		Version: record.Version,
+		Words:   make([]wordpack.Entry, len(words)),
+	}
+	for i, w := range words {
+		pack.Words[i] = wordpack.Entry{
+			Word:       w.Word,
+			Category:   w.Category,
+			Difficulty: w.Difficulty,
+			Aliases:    w.Aliases,
+			Disabled:   w.Disabled,
+		}
+	}
+	return pack, nil
+}
+
+func findPack(name string) (shared.WordPack, error) {
+	var record shared.WordPack
+	res := DB.Where("name = ?", name).Limit(1).Find(&record)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return record, ErrPackNotFound
+	}
+	return record, res.Error
+}
+
+// ListPacks lists every word pack by name.
+func ListPacks() ([]PackSummary, error) {
+	var packs []PackSummary
+	err := DB.Model(&shared.WordPack{}).
+		Select(`word_packs.*,
+			COUNT(words.id) AS words,
+			COUNT(CASE WHEN words.disabled THEN 1 END) AS disabled`).
+		Joins("LEFT JOIN pack_words ON pack_words.pack_id = word_packs.id").
+		Joins("LEFT JOIN words ON words.id = pack_words.word_id").
+		Group("word_packs.id").
+		Order("word_packs.name").
+		Scan(&packs).Error
+	return packs, err
+}
+
+// SetPackDisabled enables or disables every word in the pack named name,
+// including words other packs hold too. It returns how many words changed.
+func SetPackDisabled(name string, disabled bool) (int, error) {
+	record, err := findPack(name)
+	if err != nil {
+		return 0, err
+	}
+	res := packWords(DB.Model(&shared.Word{}), record.Id).
+		Where("disabled <> ?", disabled).
+		Update("disabled", disabled)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	InvalidateWordIndex()
+	return int(res.RowsAffected), nil
+}
+
+// DeletePack removes the pack named name and those of its words that no
+// other pack holds. It returns how many words were removed.
+func DeletePack(name string) (int, error) {
+	record, err := findPack(name)
+	if err != nil {
+		return 0, err
+	}
+	var removed int
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uint64
+		if err := tx.Model(&shared.PackWord{}).Where("pack_id = ?", record.Id).Pluck("word_id", &ids).Error; err != nil {
+			return err
+		}
+		if removed, err = removeFromPack(tx, record.Id, ids); err != nil {
+			return err
+		}
+		return tx.Delete(&record).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	InvalidateWordIndex()
+	return removed, nil
+}
+
+// ListWords returns the words matching query, disabled ones included, sorted
+// by category and word, along with how many match in total.
+func ListWords(query WordQuery) ([]shared.Word, int64, error) {
+	tx := DB.Model(&shared.Word{})
+	if query.Category != "" {
+		tx = tx.Where("category = ?", query.Category)
+	}
+	if query.Pack != "" {
+		tx = tx.Where("id IN (?)", DB.Model(&shared.PackWord{}).Select("word_id").
+			Where("pack_id IN (?)", DB.Model(&shared.WordPack{}).Select("id").Where("name = ?", query.Pack)))
+	}
+	if query.Search != "" {
+		tx = tx.Where("LOWER(word) LIKE ?", "%"+strings.ToLower(query.Search)+"%")
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+	if query.Offset > 0 {
+		tx = tx.Offset(query.Offset)
+	}
+	var words []shared.Word
+	if err := tx.Order("category, word COLLATE NOCASE").Find(&words).Error; err != nil {
+		return nil, 0, err
+	}
+	return words, total, nil
+}
+
+// AddWord adds a single word to the word bank, outside of any pack. Its
+// difficulty is estimated when empty.
+func AddWord(word *shared.Word) error {
+	entry := wordpack.Entry{
+		Word:       word.Word,
+		Category:   word.Category,
+		Difficulty: word.Difficulty,
+		Aliases:    word.Aliases,
+	}
+	if err := entry.Normalize(); err != nil {
+		return err
+	}
+	*word = shared.Word{
+		Word:       entry.Word,
+		Category:   entry.Category,
+		Difficulty: entry.Difficulty,
+		Aliases:    entry.Aliases,
+		Disabled:   word.Disabled,
+	}
+	// The unique index, rather than a lookup first, catches duplicates so two
+	// concurrent adds cannot both succeed.
+	if err := DB.Create(word).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrDuplicateWord
+		}
+		return err
+	}
+	InvalidateWordIndex()
+	return nil
+}
+
+// SetWordDisabled enables or disables the word with the given ID and returns
+// it.
+func SetWordDisabled(id uint64, disabled bool) (shared.Word, error) {
+	var word shared.Word
+	res := DB.Limit(1).Find(&word, id)
+	if res.Error != nil {
+		return word, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return word, ErrWordNotFound
+	}
+	if err := DB.Model(&word).Update("disabled", disabled).Error; err != nil {
+		return word, err
+	}
+	InvalidateWordIndex()
+	return word, nil
+}
+
+// DeleteWord removes the word with the given ID from the word bank and from
+// every pack.
+func DeleteWord(id uint64) error {
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&shared.Word{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrWordNotFound
+		}
+		return tx.Where("word_id = ?", id).Delete(&shared.PackWord{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	InvalidateWordIndex()
+	return nil
+}
@@ -0,0 +1,219 @@
+package db_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Ajstraight619/pictionary-server/internal/db"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/Ajstraight619/pictionary-server/internal/wordpack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func animalsPack() wordpack.Pack {
+	return wordpack.Pack{
+		Name:    "animals",
+		Version: "1",
+		Words: []wordpack.Entry{
+			{Word: "Ants", Category: "Animals", Difficulty: shared.DifficultyEasy},
+			{Word: "Koala", Category: "Animals", Aliases: []string{"Koala bear"}},
+			{Word: "Albatross", Category: "Animals"},
+		},
+	}
+}
+
+func TestImportPackIsIdempotent(t *testing.T) {
+	useWords(t, shared.Word{Word: "koala", Category: "Animals"})
+
+	result, err := db.ImportPack(animalsPack())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Added)
+	assert.Equal(t, 1, result.Updated, "the koala already in the bank moves into the pack")
+	assert.Equal(t, "1", result.Pack.Version)
+
+	result, err = db.ImportPack(animalsPack())
+	require.NoError(t, err)
+	assert.Equal(t, db.ImportResult{Pack: result.Pack, Unchanged: 3}, result)
+
+	var count int64
+	require.NoError(t, db.DB.Model(&shared.Word{}).Count(&count).Error)
+	assert.EqualValues(t, 3, count)
+
+	exported, err := db.ExportPack("animals")
+	require.NoError(t, err)
+	assert.Equal(t, wordpack.Pack{
+		Name:    "animals",
+		Version: "1",
+		Words: []wordpack.Entry{
+			{Word: "Albatross", Category: "Animals", Difficulty: shared.DifficultyHard},
+			{Word: "Ants", Category: "Animals", Difficulty: shared.DifficultyEasy},
+			{Word: "Koala", Category: "Animals", Difficulty: shared.DifficultyEasy, Aliases: []string{"Koala bear"}},
+		},
+	}, exported)
+}
+
+func TestPacksShareWords(t *testing.T) {
+	useWords(t)
+	_, err := db.ImportPack(animalsPack())
+	require.NoError(t, err)
+
+	zoo := wordpack.Pack{
+		Name:    "zoo",
+		Version: "1",
+		Words: []wordpack.Entry{
+			{Word: "koala", Category: "Animals", Difficulty: shared.DifficultyHard},
+			{Word: "Zebra", Category: "Animals"},
+		},
+	}
+	result, err := db.ImportPack(zoo)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Added)
+	assert.Equal(t, 1, result.Updated, "the koala joins the zoo pack")
+
+	// Importing either pack again leaves the shared koala where it is.
+	result, err = db.ImportPack(animalsPack())
+	require.NoError(t, err)
+	assert.Equal(t, db.ImportResult{Pack: result.Pack, Unchanged: 3}, result)
+	result, err = db.ImportPack(zoo)
+	require.NoError(t, err)
+	assert.Equal(t, db.ImportResult{Pack: result.Pack, Unchanged: 2}, result)
+
+	exported, err := db.ExportPack("zoo")
+	require.NoError(t, err)
+	require.Len(t, exported.Words, 2)
+	assert.Equal(t, wordpack.Entry{
+		Word: "Koala", Category: "Animals", Difficulty: shared.DifficultyEasy, Aliases: []string{"Koala bear"},
+	}, exported.Words[0], "the animals pack imported the koala first")
+	exported, err = db.ExportPack("animals")
+	require.NoError(t, err)
+	assert.Len(t, exported.Words, 3)
+
+	removed, err := db.DeletePack("animals")
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed, "the koala stays for the zoo pack")
+	words, _, err := db.ListWords(db.WordQuery{Pack: "zoo"})
+	require.NoError(t, err)
+	require.Len(t, words, 2)
+	assert.Equal(t, "Koala", words[0].Word)
+
+	removed, err = db.DeletePack("zoo")
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	_, total, err := db.ListWords(db.WordQuery{})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestImportPackNewVersion(t *testing.T) {
+	useWords(t)
+	_, err := db.ImportPack(animalsPack())
+	require.NoError(t, err)
+	words, _, err := db.ListWords(db.WordQuery{Search: "ants"})
+	require.NoError(t, err)
+	require.Len(t, words, 1)
+	_, err = db.SetWordDisabled(words[0].Id, true)
+	require.NoError(t, err)
+
+	pack := animalsPack()
+	pack.Version = "2"
+	pack.Words = append(pack.Words[:2], wordpack.Entry{Word: "Zebra", Category: "Animals"})
+	pack.Words[1].Difficulty = shared.DifficultyHard
+	result, err := db.ImportPack(pack)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Added)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, 1, result.Removed)
+
+	exported, err := db.ExportPack("animals")
+	require.NoError(t, err)
+	assert.Equal(t, "2", exported.Version)
+	require.Len(t, exported.Words, 3)
+	assert.Equal(t, "Ants", exported.Words[0].Word)
+	assert.True(t, exported.Words[0].Disabled, "importing keeps words disabled")
+	assert.Equal(t, shared.DifficultyHard, exported.Words[1].Difficulty)
+	assert.Equal(t, "Zebra", exported.Words[2].Word)
+}
+
+func TestImportPackRejectsInvalidPacks(t *testing.T) {
+	useWords(t)
+
+	_, err := db.ImportPack(wordpack.Pack{Words: animalsPack().Words})
+	assert.Error(t, err, "a pack needs a name")
+
+	pack := animalsPack()
+	pack.Words = append(pack.Words, wordpack.Entry{Word: "ANTS", Category: "Animals"})
+	_, err = db.ImportPack(pack)
+	assert.Error(t, err, "a word may not repeat within a category")
+
+	packs, err := db.ListPacks()
+	require.NoError(t, err)
+	assert.Empty(t, packs)
+}
+
+func TestDisabledWordsAreNeverOffered(t *testing.T) {
+	useWords(t)
+	rng := rand.New(rand.NewSource(1))
+	_, err := db.ImportPack(animalsPack())
+	require.NoError(t, err)
+	word := shared.Word{Word: "Tennis", Category: "Sports"}
+	require.NoError(t, db.AddWord(&word))
+	assert.Equal(t, shared.DifficultyMedium, word.Difficulty)
+	assert.ErrorIs(t, db.AddWord(&shared.Word{Word: "tennis", Category: "Sports"}), db.ErrDuplicateWord)
+
+	disabled, err := db.SetPackDisabled("animals", true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, disabled)
+
+	words, err := db.GetRandomWords(rng, 10, db.WordFilter{})
+	require.NoError(t, err)
+	require.Len(t, words, 1)
+	assert.Equal(t, "Tennis", words[0].Word)
+
+	categories, err := db.GetCategories()
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, "Sports", categories[0].Name)
+
+	packs, err := db.ListPacks()
+	require.NoError(t, err)
+	require.Len(t, packs, 1)
+	assert.Equal(t, "animals", packs[0].Name)
+	assert.Equal(t, 3, packs[0].Words)
+	assert.Equal(t, 3, packs[0].Disabled)
+
+	_, err = db.SetPackDisabled("animals", false)
+	require.NoError(t, err)
+	words, err = db.GetRandomWords(rng, 10, db.WordFilter{})
+	require.NoError(t, err)
+	assert.Len(t, words, 4)
+}
+
+func TestDeleteWordsAndPacks(t *testing.T) {
+	useWords(t)
+	_, err := db.ImportPack(animalsPack())
+	require.NoError(t, err)
+	word := shared.Word{Word: "Tennis", Category: "Sports"}
+	require.NoError(t, db.AddWord(&word))
+
+	words, total, err := db.ListWords(db.WordQuery{Pack: "animals", Limit: 2})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, total)
+	require.Len(t, words, 2)
+	assert.Equal(t, "Albatross", words[0].Word)
+
+	require.NoError(t, db.DeleteWord(words[0].Id))
+	assert.ErrorIs(t, db.DeleteWord(words[0].Id), db.ErrWordNotFound)
+
+	removed, err := db.DeletePack("animals")
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	_, err = db.DeletePack("animals")
+	assert.ErrorIs(t, err, db.ErrPackNotFound)
+
+	words, total, err = db.ListWords(db.WordQuery{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.Equal(t, "Tennis", words[0].Word)
+}
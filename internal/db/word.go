@@ -1,13 +1,264 @@
 package db
 
 import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/Ajstraight619/pictionary-server/internal/wordpack"
+	"gorm.io/gorm"
 )
 
-func GetRandomWords(n int) ([]shared.Word, error) {
-	var words []shared.Word
-	if err := DB.Order("RANDOM()").Limit(n).Find(&words).Error; err != nil {
+// WordFilter narrows the words GetRandomWords picks from. Zero fields match
+// every word.
+type WordFilter struct {
+	Categories []string
+	Difficulty string
+	Exclude    []uint64
+}
+
+// wordKey groups words in the index.
+type wordKey struct {
+	category, difficulty string
+}
+
+// wordIndex holds the ID of every enabled word, grouped by category and
+// difficulty, so picking random words costs a primary key lookup instead of a scan of
+// the whole table.
+type wordIndex struct {
+	mu sync.Mutex
+	// words is nil until the index is loaded.
+	words *wordSet
+	// generation counts invalidations, so that a load which raced with one
+	// is not kept.
+	generation int
+}
+
+// wordSet is a loaded word index. It is never modified once built.
+type wordSet struct {
+	// keys lists the groups in ids in sorted order.
+	keys []wordKey
+	// ids holds the sorted IDs in each group.
+	ids map[wordKey][]uint64
+}
+
+var index wordIndex
+
+// InvalidateWordIndex makes the next GetRandomWords reload the word index. It
+// must be called after words are added, removed, enabled or disabled.
+func InvalidateWordIndex() {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	index.words = nil
+	index.generation++
+}
+
+// load returns the word index, reading it from the database if it is not
+// loaded.
+func (x *wordIndex) load() (*wordSet, error) {
+	x.mu.Lock()
+	words, generation := x.words, x.generation
+	x.mu.Unlock()
+	if words != nil {
+		return words, nil
+	}
+
+	var rows []shared.Word
+	if err := DB.Select("id", "category", "difficulty").Where("disabled = ?", false).Find(&rows).Error; err != nil {
 		return nil, err
 	}
+	words = &wordSet{ids: make(map[wordKey][]uint64)}
+	for _, w := range rows {
+		key := wordKey{w.Category, w.Difficulty}
+		if _, ok := words.ids[key]; !ok {
+			words.keys = append(words.keys, key)
+		}
+		words.ids[key] = append(words.ids[key], w.Id)
+	}
+	slices.SortFunc(words.keys, func(a, b wordKey) int {
+		return cmp.Or(cmp.Compare(a.category, b.category), cmp.Compare(a.difficulty, b.difficulty))
+	})
+	for _, group := range words.ids {
+		slices.Sort(group)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	// If the index was invalidated while loading, the rows may already be
+	// stale. They still serve this call, but the next one loads again.
+	if x.generation == generation {
+		x.words = words
+	}
 	return words, nil
 }
+
+// candidates returns the IDs of the words matching filter, in an order that
+// only depends on the word bank so the same random source always picks the
+// same words.
+func (w *wordSet) candidates(filter WordFilter) []uint64 {
+	excluded := make(map[uint64]bool, len(filter.Exclude))
+	for _, id := range filter.Exclude {
+		excluded[id] = true
+	}
+
+	var ids []uint64
+	for _, key := range w.keys {
+		if len(filter.Categories) > 0 && !slices.Contains(filter.Categories, key.category) {
+			continue
+		}
+		if filter.Difficulty != "" && key.difficulty != filter.Difficulty {
+			continue
+		}
+		for _, id := range w.ids[key] {
+			if !excluded[id] {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// GetRandomWords returns up to n random words matching filter, chosen using
+// rng.
+func GetRandomWords(rng *rand.Rand, n int, filter WordFilter) ([]shared.Word, error) {
+	words, err := index.load()
+	if err != nil {
+		return nil, err
+	}
+	ids := words.candidates(filter)
+	n = min(n, len(ids))
+	if n == 0 {
+		return nil, nil
+	}
+	// Shuffle just the first n IDs into place.
+	for i := range n {
+		j := i + rng.Intn(len(ids)-i)
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+	ids = ids[:n]
+
+	var found []shared.Word
+	if err := DB.Find(&found, ids).Error; err != nil {
+		return nil, err
+	}
+	// Keep the random order; the database returns rows sorted by ID.
+	slices.SortFunc(found, func(a, b shared.Word) int {
+		return slices.Index(ids, a.Id) - slices.Index(ids, b.Id)
+	})
+	return found, nil
+}
+
+// CountWords returns how many words match filter.
+func CountWords(filter WordFilter) (int, error) {
+	words, err := index.load()
+	if err != nil {
+		return 0, err
+	}
+	return len(words.candidates(filter)), nil
+}
+
+// GetCategories lists the categories in the word bank by name, with how many
+// enabled words each has at every difficulty.
+func GetCategories() ([]shared.WordCategory, error) {
+	var rows []struct {
+		Category   string
+		Difficulty string
+		Count      int
+	}
+	err := DB.Model(&shared.Word{}).
+		Select("category, difficulty, COUNT(*) AS count").
+		Where("disabled = ?", false).
+		Group("category, difficulty").
+		Order("category").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []shared.WordCategory
+	for _, row := range rows {
+		if len(categories) == 0 || categories[len(categories)-1].Name != row.Category {
+			categories = append(categories, shared.WordCategory{
+				Name:         row.Category,
+				Difficulties: make(map[string]int),
+			})
+		}
+		category := &categories[len(categories)-1]
+		category.Count += row.Count
+		category.Difficulties[row.Difficulty] += row.Count
+	}
+	return categories, nil
+}
+
+// ValidateCategories checks that every category in options exists in the
+// word bank. It returns a *shared.OptionsError naming the unknown ones.
+func ValidateCategories(options shared.GameOptions) error {
+	if len(options.Categories) == 0 {
+		return nil
+	}
+	categories, err := GetCategories()
+	if err != nil {
+		return err
+	}
+	var unknown []string
+	for _, name := range options.Categories {
+		if !slices.ContainsFunc(categories, func(c shared.WordCategory) bool { return c.Name == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return &shared.OptionsError{Fields: map[string]string{
+			"categories": fmt.Sprintf("unknown categories: %s", strings.Join(unknown, ", ")),
+		}}
+	}
+	return nil
+}
+
+// MigrateWords migrates the word pack and words tables. Words repeated within a category,
+// ignoring case, are removed first so the unique index can be built. Words
+// stored before difficulties existed get the difficulty word packs estimate
+// for them.
+func MigrateWords() error {
+	backfill := false
+	if DB.Migrator().HasTable(&shared.Word{}) {
+		err := DB.Exec(`DELETE FROM words WHERE id NOT IN (
+			SELECT MIN(id) FROM words GROUP BY LOWER(word), category
+		)`).Error
+		if err != nil {
+			return err
+		}
+		backfill = !DB.Migrator().HasColumn(&shared.Word{}, "Difficulty")
+	}
+	if err := DB.AutoMigrate(&shared.WordPack{}, &shared.Word{}, &shared.PackWord{}); err != nil {
+		return err
+	}
+	if backfill {
+		if err := estimateDifficulties(); err != nil {
+			return err
+		}
+	}
+	InvalidateWordIndex()
+	return nil
+}
+
+// estimateDifficulties sets the difficulty of every word to the one
+// wordpack.EstimateDifficulty gives it.
+func estimateDifficulties() error {
+	var words []shared.Word
+	if err := DB.Select("id", "word").Find(&words).Error; err != nil {
+		return err
+	}
+	return DB.Transaction(func(tx *gorm.DB) error {
+		for _, w := range words {
+			err := tx.Model(&w).Update("difficulty", wordpack.EstimateDifficulty(w.Word)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package db_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Ajstraight619/pictionary-server/internal/db"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// useWords starts each test from a words table created before difficulties,
+// word packs and the unique index existed.
+func useWords(t *testing.T, words ...shared.Word) {
+	t.Helper()
+	db.InitDB("file:" + t.Name() + "?mode=memory&cache=shared")
+	require.NoError(t, db.DB.Exec(`DROP TABLE IF EXISTS words`).Error)
+	require.NoError(t, db.DB.Exec(`DROP TABLE IF EXISTS word_packs`).Error)
+	require.NoError(t, db.DB.Exec(`DROP TABLE IF EXISTS pack_words`).Error)
+	require.NoError(t, db.DB.Exec(`CREATE TABLE words (id integer PRIMARY KEY AUTOINCREMENT, word text, category text)`).Error)
+	for _, w := range words {
+		require.NoError(t, db.DB.Exec(`INSERT INTO words (word, category) VALUES (?, ?)`, w.Word, w.Category).Error)
+	}
+	require.NoError(t, db.MigrateWords())
+}
+
+func TestMigrateWordsRemovesDuplicates(t *testing.T) {
+	useWords(t,
+		shared.Word{Word: "Dragonfly", Category: "Animals"},
+		shared.Word{Word: "dragonfly", Category: "Animals"},
+		shared.Word{Word: "Dragonfly", Category: "Random"},
+		shared.Word{Word: "Koala", Category: "Animals"},
+	)
+
+	var words []shared.Word
+	require.NoError(t, db.DB.Order("id").Find(&words).Error)
+	require.Len(t, words, 3)
+	assert.Equal(t, "Dragonfly", words[0].Word)
+	assert.Equal(t, shared.DifficultyHard, words[0].Difficulty)
+	assert.Equal(t, shared.DifficultyEasy, words[2].Difficulty)
+
+	// Difficulties are only estimated once, when the column is added.
+	require.NoError(t, db.DB.Model(&words[2]).Update("difficulty", shared.DifficultyMedium).Error)
+	require.NoError(t, db.MigrateWords())
+	require.NoError(t, db.DB.First(&words[2], words[2].Id).Error)
+	assert.Equal(t, shared.DifficultyMedium, words[2].Difficulty)
+
+	err := db.DB.Create(&shared.Word{Word: "KOALA", Category: "Animals"}).Error
+	assert.Error(t, err, "the unique index should reject the same word in a category")
+}
+
+func TestGetRandomWords(t *testing.T) {
+	useWords(t,
+		shared.Word{Word: "Ants", Category: "Animals"},
+		shared.Word{Word: "Kangaroo", Category: "Animals"},
+		shared.Word{Word: "Tennis", Category: "Sports"},
+	)
+	rng := rand.New(rand.NewSource(1))
+
+	words, err := db.GetRandomWords(rng, 5, db.WordFilter{Categories: []string{"Animals"}})
+	require.NoError(t, err)
+	assert.Len(t, words, 2)
+
+	words, err = db.GetRandomWords(rng, 5, db.WordFilter{Difficulty: shared.DifficultyEasy})
+	require.NoError(t, err)
+	require.Len(t, words, 1)
+	assert.Equal(t, "Ants", words[0].Word)
+
+	words, err = db.GetRandomWords(rng, 5, db.WordFilter{Exclude: []uint64{words[0].Id}})
+	require.NoError(t, err)
+	for _, w := range words {
+		assert.NotEqual(t, "Ants", w.Word)
+	}
+
+	// Words added later are found once the index is invalidated.
+	require.NoError(t, db.DB.Create(&shared.Word{Word: "Golf", Category: "Sports"}).Error)
+	db.InvalidateWordIndex()
+	words, err = db.GetRandomWords(rng, 5, db.WordFilter{Categories: []string{"Sports"}})
+	require.NoError(t, err)
+	assert.Len(t, words, 2)
+
+	// Sources seeded alike pick the same words in the same order.
+	first, err := db.GetRandomWords(rand.New(rand.NewSource(7)), 3, db.WordFilter{})
+	require.NoError(t, err)
+	second, err := db.GetRandomWords(rand.New(rand.NewSource(7)), 3, db.WordFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestGetCategories(t *testing.T) {
+	useWords(t,
+		shared.Word{Word: "Ants", Category: "Animals"},
+		shared.Word{Word: "Koala", Category: "Animals"},
+		shared.Word{Word: "Tennis", Category: "Sports"},
+	)
+
+	categories, err := db.GetCategories()
+	require.NoError(t, err)
+	assert.Equal(t, []shared.WordCategory{
+		{Name: "Animals", Count: 2, Difficulties: map[string]int{shared.DifficultyEasy: 2}},
+		{Name: "Sports", Count: 1, Difficulties: map[string]int{shared.DifficultyMedium: 1}},
+	}, categories)
+}
+
+func TestWordIndexReloadsAfterConcurrentChange(t *testing.T) {
+	useWords(t, shared.Word{Word: "Ants", Category: "Animals"})
+
+	// Add a word while the index is being loaded, once its query has read the
+	// table.
+	added := false
+	require.NoError(t, db.DB.Callback().Query().After("gorm:query").Register("test:add_word", func(tx *gorm.DB) {
+		if added || tx.Statement.Table != "words" {
+			return
+		}
+		added = true
+		require.NoError(t, db.AddWord(&shared.Word{Word: "Kangaroo", Category: "Animals"}))
+	}))
+	t.Cleanup(func() { db.DB.Callback().Query().Remove("test:add_word") })
+
+	count, err := db.CountWords(db.WordFilter{})
+	require.NoError(t, err)
+	require.True(t, added)
+	assert.Equal(t, 1, count, "the load that raced still serves its caller")
+
+	count, err = db.CountWords(db.WordFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "but is not kept")
+}
@@ -0,0 +1,19 @@
+package events
+
+// ChatPayload is a chat message sent by a client.
+type ChatPayload struct {
+	Text string `json:"text"`
+}
+
+// ChatMessage relays a chat message to players. The sender's details are
+// filled in by the server, never taken from the client.
+type ChatMessage struct {
+	PlayerID string `json:"playerID"`
+	Username string `json:"username"`
+	Color    string `json:"color"`
+	Text     string `json:"text"`
+}
+
+// Chat is sent by clients with a ChatPayload and relayed by the server as a
+// ChatMessage.
+const Chat = "chatMessage"
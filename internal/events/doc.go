@@ -0,0 +1,13 @@
+// Package events defines the messages exchanged with clients over a game's
+// WebSocket connection. Every message is a JSON object with a "type" and a
+// "payload". Clients may add a "requestID" to their events to have it echoed
+// back in the matching ack or error.
+//
+// The types here describe the current protocol version. Clients pick a
+// version with the "pictionary.v<N>" WebSocket subprotocol or the "v" query
+// parameter; a client that asks for neither gets version 1, and the server
+// rewrites the messages that changed since then. The first message on a
+// connection is a welcome naming the version in use.
+package events
+
+//go:generate go run ../../cmd/eventcatalog -dir . -o ../../docs/events.md
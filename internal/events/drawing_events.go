@@ -0,0 +1,100 @@
+package events
+
+// Drawing events sent by the current drawer. The game validates them and fans
+// them back out to every client under the same type.
+
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// StrokeBeginPayload opens a new stroke. Coordinates are normalized to the
+// canvas so clients with different resolutions agree on positions.
+type StrokeBeginPayload struct {
+	Color string  `json:"color"`
+	Width float64 `json:"width"`
+	Point Point   `json:"point"`
+}
+
+// StrokePointsPayload appends a batch of points to the open stroke.
+type StrokePointsPayload struct {
+	Points []Point `json:"points"`
+}
+
+type FillPayload struct {
+	Color string `json:"color"`
+	Point Point  `json:"point"`
+}
+
+// Outgoing drawing messages. The server assigns stroke IDs so clients can
+// stitch point batches together and undo whole strokes.
+
+type StrokeBeginMessage struct {
+	StrokeID int     `json:"strokeID"`
+	Color    string  `json:"color"`
+	Width    float64 `json:"width"`
+	Point    Point   `json:"point"`
+}
+
+type StrokePointsMessage struct {
+	StrokeID int     `json:"strokeID"`
+	Points   []Point `json:"points"`
+}
+
+type StrokeEndMessage struct {
+	StrokeID int `json:"strokeID"`
+}
+
+type FillMessage struct {
+	StrokeID int    `json:"strokeID"`
+	Color    string `json:"color"`
+	Point    Point  `json:"point"`
+}
+
+type UndoMessage struct {
+	StrokeID int `json:"strokeID"`
+}
+
+// Kinds of CanvasStroke.
+const (
+	StrokeKindLine = "line"
+	StrokeKindFill = "fill"
+)
+
+// CanvasStroke is a single entry of the canvas history. Fills carry a single
+// point and no width.
+type CanvasStroke struct {
+	StrokeID int     `json:"strokeID"`
+	Kind     string  `json:"kind"`
+	Color    string  `json:"color"`
+	Width    float64 `json:"width,omitempty"`
+	Points   []Point `json:"points"`
+	Complete bool    `json:"complete"`
+}
+
+// CanvasSnapshotMessage replays the current drawing to a player who joined or
+// reconnected mid-turn.
+type CanvasSnapshotMessage struct {
+	Strokes []CanvasStroke `json:"strokes"`
+}
+
+// Drawing message types, used in both directions.
+const (
+	StrokeBegin  = "strokeBegin"
+	StrokePoints = "strokePoints"
+	StrokeEnd    = "strokeEnd"
+	Fill         = "fill"
+	Undo         = "undo"
+	ClearCanvas  = "clearCanvas"
+
+	CanvasSnapshot = "canvasSnapshot"
+)
+
+// IsDrawingEvent reports whether eventType is one of the drawing events.
+func IsDrawingEvent(eventType string) bool {
+	switch eventType {
+	case StrokeBegin, StrokePoints, StrokeEnd, Fill, Undo, ClearCanvas:
+		return true
+	}
+	return false
+}
@@ -8,11 +8,22 @@ import (
 
 // External incoming events coming from the client.
 
+// GameEvent is a message from a client. Handlers identify the sender only by
+// PlayerID; any player ID inside Payload names someone else, such as the
+// target of a kick.
 type GameEvent struct {
 	Type    string          `json:"type"`
 	Payload json.RawMessage `json:"payload"`
+	// RequestID is chosen by the client and echoed back in the ack or error
+	// the event causes, so the client can match them up.
+	RequestID string `json:"requestID,omitempty"`
+	// PlayerID is stamped by the connection that received the event and is
+	// never read from the wire.
+	PlayerID string `json:"-"`
 }
 
+// StartTimerPayload starts a named timer. Durations come from the game
+// options, so any client-supplied Duration is ignored.
 type StartTimerPayload struct {
 	TimerType string `json:"timerType"`
 	Duration  int    `json:"duration"`
@@ -22,23 +33,56 @@ type StopTimerPayload struct {
 	TimerType string `json:"timerType"`
 }
 
+// SelectWordPayload picks one of the words offered in openSelectWordModal,
+// either by its position in SelectableWords or by its ID. Word is still
+// accepted from older clients; it must also be one of the offered words.
 type SelectWordPayload struct {
-	Word shared.Word `json:"word"`
+	Index  *int         `json:"index,omitempty"`
+	WordID uint64       `json:"wordID,omitempty"`
+	Word   *shared.Word `json:"word,omitempty"`
 }
 
-type GameStatePayload struct {
+// PlayerGuessPayload is a guess from the player whose connection sent it.
+type PlayerGuessPayload struct {
+	Guess string `json:"guess"`
+}
+
+type TransferHostPayload struct {
 	PlayerID string `json:"playerID"`
 }
 
-type PlayerGuessPayload struct {
+// UpdateOptionsPayload replaces the game options. WordList, if set, is a
+// pasted word list, as JSON or one word per line, that replaces
+// Options.CustomWords.
+type UpdateOptionsPayload struct {
+	Options  shared.GameOptions `json:"options"`
+	WordList string             `json:"wordList,omitempty"`
+}
+
+// KickPlayerPayload is used by both kickPlayer and banPlayer.
+type KickPlayerPayload struct {
 	PlayerID string `json:"playerID"`
-	Guess    string `json:"guess"`
+	Reason   string `json:"reason"`
 }
 
+// WebSocket close codes sent when the server ends a connection on purpose.
+const (
+	CloseKicked   = 4000
+	CloseBanned   = 4001
+	CloseGameFull = 4002
+)
+
+// Game events sent by clients. The server also sends gameState with the
+// state of the game and playerGuess with guesses as they should be shown.
 const (
-	GameState   = "gameState"
-	PlayerGuess = "playerGuess"
-	StartTimer  = "startTimer"
-	StopTimer   = "stopTimer"
-	SelectWord  = "selectWord"
+	GameState     = "gameState"
+	PlayerGuess   = "playerGuess"
+	StartTimer    = "startTimer"
+	StopTimer     = "stopTimer"
+	SelectWord    = "selectWord"
+	RerollWords   = "rerollWords" // Asks for new words to choose from; no payload.
+	TransferHost  = "transferHost"
+	KickPlayer    = "kickPlayer"
+	BanPlayer     = "banPlayer" // Sent with a KickPlayerPayload.
+	UpdateOptions = "updateOptions"
 )
@@ -0,0 +1,71 @@
+package events
+
+// Every client event is answered with at most one error or ack, sent only to
+// the client that sent it. Events without a RequestID are not acked.
+
+// ErrorPayload tells a client why one of its events was rejected.
+type ErrorPayload struct {
+	Event string `json:"event"`
+	// RequestID echoes the RequestID of the rejected event, if it had one.
+	RequestID string `json:"requestID,omitempty"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+// AckPayload confirms that an event with a RequestID was accepted.
+type AckPayload struct {
+	Event     string `json:"event"`
+	RequestID string `json:"requestID"`
+}
+
+// Error codes sent in ErrorPayload.
+const (
+	// CodeInvalidPayload means the event could not be decoded or failed
+	// validation.
+	CodeInvalidPayload = "invalidPayload"
+	// CodeUnknownEvent means the server does not handle the event type.
+	CodeUnknownEvent = "unknownEvent"
+	// CodeBackpressure means the game was too busy to queue the event. It
+	// was dropped and may be sent again.
+	CodeBackpressure = "backpressure"
+	// CodeNotHost means only the host may send the event.
+	CodeNotHost = "notHost"
+	// CodeNotYourTurn means the event is reserved for, or forbidden to, the
+	// current drawer.
+	CodeNotYourTurn = "notYourTurn"
+	// CodeInvalidTarget means the player named in the payload cannot be
+	// acted on.
+	CodeInvalidTarget = "invalidTarget"
+	// CodeInvalidOptions means the game options were rejected.
+	CodeInvalidOptions = "invalidOptions"
+	// CodeWrongPhase means the event is not allowed in the current phase.
+	CodeWrongPhase = "wrongPhase"
+	// CodeAlreadySelected means a word was already chosen for this turn.
+	CodeAlreadySelected = "wordAlreadySelected"
+	// CodeWordNotOffered means the chosen word is not one of the words the
+	// drawer was offered.
+	CodeWordNotOffered = "wordNotOffered"
+	// CodeNoRerollsLeft means the drawer has used up their rerolls for the
+	// turn.
+	CodeNoRerollsLeft = "noRerollsLeft"
+	// CodeNoWordsLeft means there are no other words to offer the drawer.
+	CodeNoWordsLeft = "noWordsLeft"
+	// CodeCountdownEnded means the pre-game countdown can no longer be
+	// stopped.
+	CodeCountdownEnded = "countdownEnded"
+	// CodeRateLimited means the player is sending messages too quickly.
+	CodeRateLimited = "rateLimited"
+	// CodeMessageTooLong means a chat message or guess is over the length
+	// cap.
+	CodeMessageTooLong = "messageTooLong"
+	// CodeChatNotAllowed means the player may not chat right now.
+	CodeChatNotAllowed = "chatNotAllowed"
+)
+
+// Protocol message types, sent by the server.
+const (
+	// Error is sent only to the player whose event was rejected.
+	Error = "error"
+	// Ack is sent only to the player whose event was accepted.
+	Ack = "ack"
+)
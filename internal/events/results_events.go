@@ -0,0 +1,50 @@
+package events
+
+import "github.com/Ajstraight619/pictionary-server/internal/shared"
+
+// Messages sent between turns, between rounds and when the game ends.
+
+// PlayerTurnResult is what one player earned in a turn. TimeToGuessMs is only
+// set for players who guessed the word.
+type PlayerTurnResult struct {
+	PlayerID      string `json:"playerID"`
+	Username      string `json:"username"`
+	Points        int    `json:"points"`
+	Guessed       bool   `json:"guessed"`
+	TimeToGuessMs int64  `json:"timeToGuessMs,omitempty"`
+	Score         int    `json:"score"`
+}
+
+// TurnResultsMessage reveals the word once a turn is over. Word is nil when
+// the drawer left before choosing one.
+type TurnResultsMessage struct {
+	Word        *shared.Word       `json:"word"`
+	DrawerID    string             `json:"drawerID"`
+	DrawerBonus int                `json:"drawerBonus"`
+	Results     []PlayerTurnResult `json:"results"`
+}
+
+// Standing is a player's place by total score. Tied players share a rank.
+type Standing struct {
+	PlayerID string `json:"playerID"`
+	Username string `json:"username"`
+	Score    int    `json:"score"`
+	Rank     int    `json:"rank"`
+}
+
+type RoundResultsMessage struct {
+	Round     int        `json:"round"`
+	Standings []Standing `json:"standings"`
+}
+
+// PodiumMessage lists the players who placed in the top three.
+type PodiumMessage struct {
+	Places []Standing `json:"places"`
+}
+
+// Results message types, sent by the server.
+const (
+	TurnResults  = "turnResults"
+	RoundResults = "roundResults"
+	Podium       = "podium"
+)
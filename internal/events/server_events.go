@@ -0,0 +1,91 @@
+package events
+
+import "github.com/Ajstraight619/pictionary-server/internal/shared"
+
+// Messages only the server sends.
+
+// PlayerJoinedMessage announces a player who joined or reconnected.
+type PlayerJoinedMessage struct {
+	Player *shared.Player `json:"player"`
+}
+
+type PlayerLeftMessage struct {
+	PlayerID string `json:"playerID"`
+}
+
+type PlayerKickedMessage struct {
+	PlayerID string `json:"playerID"`
+	Banned   bool   `json:"banned"`
+	Reason   string `json:"reason"`
+}
+
+// DrawingPlayerChangedMessage announces the next drawer. Protocol version 1
+// sends the player as the whole payload.
+type DrawingPlayerChangedMessage struct {
+	Player *shared.Player `json:"player"`
+}
+
+// OpenSelectWordModalMessage offers the drawer the words to choose from. It
+// is sent again with new words after a reroll.
+type OpenSelectWordModalMessage struct {
+	IsSelectingWord bool          `json:"isSelectingWord"`
+	SelectableWords []shared.Word `json:"selectableWords"`
+	// RerollsLeft is how many more times the drawer may ask for new words
+	// this turn.
+	RerollsLeft int `json:"rerollsLeft"`
+}
+
+// SelectedWordMessage tells the drawer which word they are drawing.
+type SelectedWordMessage struct {
+	Word            *shared.Word `json:"word"`
+	IsSelectingWord bool         `json:"isSelectingWord"`
+}
+
+// TimerMessage is sent on every tick of a countdown.
+type TimerMessage struct {
+	TimeRemaining int `json:"timeRemaining"`
+}
+
+// RevealedLetterMessage carries the hint for the word, with '_' for each
+// hidden letter. Protocol version 1 sends the letters as the whole payload.
+type RevealedLetterMessage struct {
+	RevealedLetters []rune `json:"revealedLetters"`
+}
+
+// PlayerGuessMessage shows a guess, or a note about it, in the guess feed.
+// GuessedOnly marks messages only shown to players who know the word.
+type PlayerGuessMessage struct {
+	Guess       string `json:"guess"`
+	Username    string `json:"username"`
+	Color       string `json:"color"`
+	GuessedOnly bool   `json:"guessedOnly,omitempty"`
+}
+
+// GameEndedMessage is sent when the game shuts down.
+type GameEndedMessage struct {
+	Message string `json:"message"`
+}
+
+// WelcomeMessage is the first message on a connection. It confirms the
+// protocol version and lists the optional features the server will use.
+type WelcomeMessage struct {
+	ProtocolVersion int      `json:"protocolVersion"`
+	Capabilities    []string `json:"capabilities"`
+}
+
+// Server message types.
+const (
+	PlayerJoined         = "playerJoined"
+	PlayerReconnected    = "playerReconnected" // Sent with a PlayerJoinedMessage.
+	PlayerLeft           = "playerLeft"
+	PlayerKicked         = "playerKicked"
+	DrawingPlayerChanged = "drawingPlayerChanged"
+	OpenSelectWordModal  = "openSelectWordModal"
+	SelectedWord         = "selectedWord"
+	StartGameCountdown   = "startGameCountdown" // Sent with a TimerMessage.
+	TurnTimer            = "turnTimer"          // Sent with a TimerMessage.
+	SelectWordTimer      = "selectWordTimer"    // Sent with a TimerMessage.
+	RevealedLetter       = "revealedLetter"
+	GameEnded            = "gameEnded"
+	Welcome              = "welcome"
+)
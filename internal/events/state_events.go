@@ -0,0 +1,49 @@
+package events
+
+import (
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+)
+
+// GameStatus is whether a game is waiting to start, running or over.
+type GameStatus int
+
+// GamePhase is the stage a game is in. The phases are listed in the game
+// package.
+type GamePhase string
+
+// RoundState describes the current round.
+type RoundState struct {
+	Count           int      `json:"count"`
+	PlayersDrawn    []string `json:"playersDrawn"`
+	CurrentDrawerID string   `json:"currentDrawerID"`
+}
+
+// TurnState describes the current turn. WordToGuess and SelectableWords are
+// left out for players who may not see them.
+type TurnState struct {
+	CurrentDrawerID         string          `json:"currentDrawerID"`
+	WordToGuess             *shared.Word    `json:"wordToGuess,omitempty"`
+	RevealedLetters         []rune          `json:"revealedLetters"`
+	PlayersGuessedCorrectly map[string]bool `json:"playersGuessedCorrectly"`
+	IsSelectingWord         bool            `json:"isSelectingWord"`
+	SelectableWords         []shared.Word   `json:"selectableWords,omitempty"`
+}
+
+// GameStateMessage is a player's view of the whole game.
+type GameStateMessage struct {
+	ID              string             `json:"id"`
+	Players         []*shared.Player   `json:"players"`
+	PlayerOrder     []string           `json:"playerOrder"`
+	CurrentDrawerID string             `json:"currentDrawerID"`
+	Options         shared.GameOptions `json:"options"`
+	Status          GameStatus         `json:"status"`
+	Phase           GamePhase          `json:"phase"`
+	// PhaseDeadline is when the current phase is due to end. It is omitted
+	// for phases without a time limit.
+	PhaseDeadline   *time.Time `json:"phaseDeadline,omitempty"`
+	Round           RoundState `json:"round"`
+	Turn            TurnState  `json:"turn"`
+	IsSelectingWord bool       `json:"isSelectingWord"`
+}
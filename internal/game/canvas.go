@@ -0,0 +1,147 @@
+package game
+
+import (
+	"errors"
+	"slices"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+)
+
+const (
+	maxPointsPerStroke = 4096
+	// The history is trimmed from the oldest stroke once either limit is hit,
+	// so a long turn cannot grow the canvas without bound.
+	maxCanvasStrokes = 1000
+	maxCanvasPoints  = 50000
+)
+
+var (
+	errNoActiveStroke = errors.New("no stroke in progress")
+	errStrokeTooLong  = errors.New("stroke has too many points")
+)
+
+// Canvas holds the stroke history of the current turn. It is reset when the
+// turn ends.
+type Canvas struct {
+	nextStrokeID int
+	strokes      []e.CanvasStroke
+	points       int
+	// active is the ID of the open stroke, always the last entry of strokes.
+	active int
+}
+
+func NewCanvas() *Canvas {
+	return &Canvas{
+		strokes: []e.CanvasStroke{},
+	}
+}
+
+// Reset discards the history at the end of a turn.
+func (c *Canvas) Reset() {
+	c.nextStrokeID = 0
+	c.clear()
+}
+
+// clear wipes the history but keeps stroke IDs increasing within the turn.
+func (c *Canvas) clear() {
+	c.strokes = []e.CanvasStroke{}
+	c.points = 0
+	c.active = 0
+}
+
+func (c *Canvas) beginStroke(color string, width float64, start e.Point) int {
+	c.closeActive()
+	c.nextStrokeID++
+	c.active = c.nextStrokeID
+	c.push(e.CanvasStroke{
+		StrokeID: c.nextStrokeID,
+		Kind:     e.StrokeKindLine,
+		Color:    color,
+		Width:    width,
+		Points:   []e.Point{start},
+	})
+	return c.active
+}
+
+func (c *Canvas) appendPoints(points []e.Point) (int, error) {
+	if c.active == 0 {
+		return 0, errNoActiveStroke
+	}
+	stroke := &c.strokes[len(c.strokes)-1]
+	if len(stroke.Points)+len(points) > maxPointsPerStroke {
+		return 0, errStrokeTooLong
+	}
+	stroke.Points = append(stroke.Points, points...)
+	c.points += len(points)
+	c.trim()
+	return c.active, nil
+}
+
+func (c *Canvas) endStroke() (int, error) {
+	if c.active == 0 {
+		return 0, errNoActiveStroke
+	}
+	id := c.active
+	c.closeActive()
+	return id, nil
+}
+
+func (c *Canvas) fill(color string, at e.Point) int {
+	c.closeActive()
+	c.nextStrokeID++
+	c.push(e.CanvasStroke{
+		StrokeID: c.nextStrokeID,
+		Kind:     e.StrokeKindFill,
+		Color:    color,
+		Points:   []e.Point{at},
+		Complete: true,
+	})
+	return c.nextStrokeID
+}
+
+// undo removes the most recent stroke, open or not, and returns its ID.
+func (c *Canvas) undo() (int, bool) {
+	if len(c.strokes) == 0 {
+		return 0, false
+	}
+	last := c.strokes[len(c.strokes)-1]
+	c.strokes = c.strokes[:len(c.strokes)-1]
+	c.points -= len(last.Points)
+	if last.StrokeID == c.active {
+		c.active = 0
+	}
+	return last.StrokeID, true
+}
+
+// Snapshot returns a deep copy of the history that is safe to hand off.
+func (c *Canvas) Snapshot() []e.CanvasStroke {
+	strokes := make([]e.CanvasStroke, len(c.strokes))
+	for i, stroke := range c.strokes {
+		stroke.Points = slices.Clone(stroke.Points)
+		strokes[i] = stroke
+	}
+	return strokes
+}
+
+func (c *Canvas) push(stroke e.CanvasStroke) {
+	c.strokes = append(c.strokes, stroke)
+	c.points += len(stroke.Points)
+	c.trim()
+}
+
+func (c *Canvas) closeActive() {
+	if c.active == 0 {
+		return
+	}
+	c.strokes[len(c.strokes)-1].Complete = true
+	c.active = 0
+}
+
+// trim drops the oldest strokes until the history fits its limits. The open
+// stroke is never dropped.
+func (c *Canvas) trim() {
+	for len(c.strokes) > 1 && (len(c.strokes) > maxCanvasStrokes || c.points > maxCanvasPoints) {
+		c.points -= len(c.strokes[0].Points)
+		c.strokes = slices.Delete(c.strokes, 0, 1)
+	}
+}
@@ -0,0 +1,174 @@
+package game_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type canvasStep struct {
+	event   string
+	payload any
+}
+
+func begin() canvasStep {
+	return canvasStep{e.StrokeBegin, e.StrokeBeginPayload{Color: "#000000", Width: 4, Point: e.Point{X: 0.5, Y: 0.5}}}
+}
+
+func points(n int) canvasStep {
+	return canvasStep{e.StrokePoints, e.StrokePointsPayload{Points: make([]e.Point, n)}}
+}
+
+func fill() canvasStep {
+	return canvasStep{e.Fill, e.FillPayload{Color: "#ff0000", Point: e.Point{X: 0.1, Y: 0.1}}}
+}
+
+var (
+	endStroke   = canvasStep{e.StrokeEnd, nil}
+	undoStroke  = canvasStep{e.Undo, nil}
+	clearCanvas = canvasStep{e.ClearCanvas, nil}
+)
+
+// strokeSummary is the part of a snapshot stroke the tests compare.
+type strokeSummary struct {
+	ID       int
+	Kind     string
+	Points   int
+	Complete bool
+}
+
+// drawingGame starts a game and waits for its first drawing phase.
+func drawingGame(t *testing.T) *gametest.Simulator {
+	t.Helper()
+	sim := newTestGame(t, 2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+	return sim
+}
+
+func draw(sim *gametest.Simulator, steps ...canvasStep) {
+	drawer := sim.Turn().DrawerID
+	for _, step := range steps {
+		sim.Send(drawer, step.event, step.payload)
+	}
+}
+
+// snapshotFor sends playerID the canvas and returns what they received.
+func snapshotFor(t *testing.T, sim *gametest.Simulator, playerID string) []e.CanvasStroke {
+	t.Helper()
+	sim.Game.SendCanvasSnapshot(playerID)
+	msg, ok := sim.Messenger.Last(playerID, e.CanvasSnapshot)
+	require.True(t, ok, "no snapshot sent to %s", playerID)
+	require.Equal(t, playerID, msg.To)
+	var snapshot e.CanvasSnapshotMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &snapshot))
+	return snapshot.Strokes
+}
+
+func summarize(strokes []e.CanvasStroke) []strokeSummary {
+	summaries := []strokeSummary{}
+	for _, s := range strokes {
+		summaries = append(summaries, strokeSummary{s.StrokeID, s.Kind, len(s.Points), s.Complete})
+	}
+	return summaries
+}
+
+func TestCanvasHistory(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		steps []canvasStep
+		want  []strokeSummary
+	}{
+		{
+			name:  "finished stroke",
+			steps: []canvasStep{begin(), points(3), endStroke},
+			want:  []strokeSummary{{1, e.StrokeKindLine, 4, true}},
+		},
+		{
+			name:  "open stroke",
+			steps: []canvasStep{begin(), points(2)},
+			want:  []strokeSummary{{1, e.StrokeKindLine, 3, false}},
+		},
+		{
+			name:  "fill closes the open stroke",
+			steps: []canvasStep{begin(), fill()},
+			want:  []strokeSummary{{1, e.StrokeKindLine, 1, true}, {2, e.StrokeKindFill, 1, true}},
+		},
+		{
+			name:  "undo removes the last stroke",
+			steps: []canvasStep{begin(), endStroke, fill(), undoStroke},
+			want:  []strokeSummary{{1, e.StrokeKindLine, 1, true}},
+		},
+		{
+			name:  "undo of the open stroke ends it",
+			steps: []canvasStep{begin(), undoStroke, points(1), undoStroke},
+			want:  []strokeSummary{},
+		},
+		{
+			name:  "clear keeps stroke IDs increasing",
+			steps: []canvasStep{begin(), endStroke, clearCanvas, fill()},
+			want:  []strokeSummary{{2, e.StrokeKindFill, 1, true}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := drawingGame(t)
+			draw(sim, tt.steps...)
+			late := sim.Join("late")
+			assert.Equal(t, tt.want, summarize(snapshotFor(t, sim, late)))
+		})
+	}
+}
+
+func TestCanvasTrim(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		steps   []canvasStep
+		strokes int
+		firstID int
+	}{
+		{
+			name:    "too many strokes",
+			steps:   repeat(1001, fill()),
+			strokes: 1000,
+			firstID: 2,
+		},
+		{
+			// Each stroke holds 1 + 31*128 = 3969 points, so thirteen of them
+			// go over the 50000 point limit.
+			name:    "too many points",
+			steps:   repeat(13, append([]canvasStep{begin()}, repeat(31, points(128))...)...),
+			strokes: 12,
+			firstID: 2,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := drawingGame(t)
+			draw(sim, tt.steps...)
+			strokes := snapshotFor(t, sim, sim.Host())
+			require.Len(t, strokes, tt.strokes)
+			assert.Equal(t, tt.firstID, strokes[0].StrokeID)
+		})
+	}
+}
+
+func TestCanvasResetsBetweenTurns(t *testing.T) {
+	sim := drawingGame(t)
+	draw(sim, begin(), endStroke)
+	require.Len(t, snapshotFor(t, sim, sim.Host()), 1)
+
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseTurnResults })
+	assert.Empty(t, snapshotFor(t, sim, sim.Host()))
+}
+
+func repeat(n int, steps ...canvasStep) []canvasStep {
+	var repeated []canvasStep
+	for range n {
+		repeated = append(repeated, steps...)
+	}
+	return repeated
+}
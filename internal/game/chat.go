@@ -0,0 +1,75 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+)
+
+const (
+	// maxChatLength caps chat messages and guesses, in characters.
+	maxChatLength = 200
+	// Each player may send chatBurst messages at once and earns another
+	// every chatInterval.
+	chatBurst    = 5
+	chatInterval = time.Second
+)
+
+// SetBlocklist sets the words masked in chat. Like RegisterGameEvent, it must
+// be called before Run starts.
+func (g *Game) SetBlocklist(blocklist *chat.Blocklist) {
+	g.blocklist = blocklist
+}
+
+// acceptChat checks a chat message or guess from playerID against the length
+// cap and their rate limit, telling them if it is rejected. It returns the
+// trimmed text.
+func (g *Game) acceptChat(playerID, eventType, text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		g.sendError(playerID, eventType, e.CodeInvalidPayload, "Messages cannot be empty")
+		return "", false
+	}
+	if utf8.RuneCountInString(text) > maxChatLength {
+		g.sendError(playerID, eventType, e.CodeMessageTooLong, fmt.Sprintf("Messages can be at most %d characters", maxChatLength))
+		return "", false
+	}
+	if !g.chatLimiter.Allow(playerID, g.clock.Now()) {
+		g.sendError(playerID, eventType, e.CodeRateLimited, "You are sending messages too quickly")
+		return "", false
+	}
+	return text, true
+}
+
+// handleChat relays a chat message. While a word is being drawn, messages
+// from guessers are checked as guesses so the answer cannot be posted in
+// chat, and the drawer may not chat at all.
+func (g *Game) handleChat(playerID, text string) {
+	if _, ok := g.Players[playerID]; !ok {
+		return
+	}
+	text, ok := g.acceptChat(playerID, e.Chat, text)
+	if !ok {
+		return
+	}
+
+	if g.Phase == PhaseDrawing {
+		if playerID == g.CurrentTurn.CurrentDrawerID {
+			g.sendError(playerID, e.Chat, e.CodeChatNotAllowed, "The drawer cannot chat while drawing")
+			return
+		}
+		g.handlePlayerGuess(playerID, e.Chat, text)
+		return
+	}
+
+	g.broadcast(e.Chat, e.ChatMessage{
+		PlayerID: playerID,
+		Username: g.Players[playerID].Username,
+		Color:    g.getPlayerColor(playerID),
+		Text:     g.blocklist.Filter(text),
+	})
+}
@@ -0,0 +1,89 @@
+package game_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/messaging/messagingtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLobbyChat(t *testing.T) {
+	sim := newTestGame(t, 2)
+	sim.Game.SetBlocklist(chat.NewBlocklist("darn"))
+	sender := sim.Players[1]
+
+	sim.Send(sender, e.Chat, e.ChatPayload{Text: "  oh darn, hello  "})
+	sim.Settle()
+
+	msg, ok := sim.Messenger.Last(sim.Players[0], e.Chat)
+	require.True(t, ok)
+	assert.Empty(t, msg.To, "lobby chat should be broadcast")
+	var chatMsg e.ChatMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &chatMsg))
+	player := sim.Game.GetPlayerByID(sender)
+	assert.Equal(t, e.ChatMessage{
+		PlayerID: sender,
+		Username: player.Username,
+		Color:    player.Color,
+		Text:     "oh ****, hello",
+	}, chatMsg)
+}
+
+func TestChatLimits(t *testing.T) {
+	sim := newTestGame(t, 2)
+	sender := sim.Players[1]
+
+	sim.Send(sender, e.Chat, e.ChatPayload{Text: strings.Repeat("a", 201)})
+	sim.Settle()
+	assert.Equal(t, e.CodeMessageTooLong, lastError(t, sim, sender).Code)
+
+	for range 6 {
+		sim.Send(sender, e.Chat, e.ChatPayload{Text: "spam"})
+	}
+	sim.Settle()
+	assert.Equal(t, e.CodeRateLimited, lastError(t, sim, sender).Code)
+	assert.Len(t, messagesOf(sim, e.Chat), 5)
+
+	// The limit recovers with time.
+	sim.Clock.Advance(time.Second)
+	sim.Send(sender, e.Chat, e.ChatPayload{Text: "again"})
+	sim.Settle()
+	assert.Len(t, messagesOf(sim, e.Chat), 6)
+}
+
+func TestChatDuringDrawingIsAGuess(t *testing.T) {
+	sim := newTestGame(t, 2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+
+	drawer, guesser := sim.Turn().DrawerID, guessersOf(sim)[0]
+	sim.Messenger.Reset()
+
+	sim.Send(drawer, e.Chat, e.ChatPayload{Text: "hint hint"})
+	sim.Settle()
+	assert.Equal(t, e.CodeChatNotAllowed, lastError(t, sim, drawer).Code)
+
+	sim.Send(guesser, e.Chat, e.ChatPayload{Text: sim.Turn().Word.Word})
+	sim.Settle()
+	assert.Empty(t, messagesOf(sim, e.Chat), "the answer was relayed as chat")
+	assert.Equal(t, g.PhaseTurnResults, sim.Phase())
+}
+
+// messagesOf returns every recorded message of type msgType.
+func messagesOf(sim *gametest.Simulator, msgType string) []messagingtest.Message {
+	var msgs []messagingtest.Message
+	for _, msg := range sim.Messenger.Messages() {
+		if msg.Type == msgType {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
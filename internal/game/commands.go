@@ -0,0 +1,108 @@
+package game
+
+import (
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+)
+
+// Game state is owned by the Run goroutine. Handlers, timers and HTTP
+// requests never touch it directly; they send commands that Run executes one
+// at a time, so none of the state needs locking. Unexported methods assume
+// they are running on the Run goroutine.
+
+// do runs fn on the Run goroutine and waits for it to finish. It reports
+// false if the game has stopped. It must not be called from the Run
+// goroutine itself.
+func (g *Game) do(fn func()) bool {
+	done := make(chan struct{})
+	select {
+	case g.commands <- func() { defer close(done); fn() }:
+	case <-g.stopped:
+		return false
+	case <-g.ctx.Done():
+		return false
+	}
+	// The channel is buffered, so Run may stop before it gets to fn.
+	select {
+	case <-done:
+		return true
+	case <-g.stopped:
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// query runs fn, which must only read state, on the Run goroutine. Once the
+// game has stopped its state no longer changes, so fn then runs on the
+// caller's goroutine instead.
+func (g *Game) query(fn func()) {
+	if g.do(fn) {
+		return
+	}
+	select {
+	case <-g.stopped:
+		fn()
+	default:
+	}
+}
+
+// Sync waits until the game loop has run every command queued before it,
+// along with the flow events they signalled and the results of any work they
+// started with offLoop. Tests use it to wait for the game to react.
+func (g *Game) Sync() {
+	g.do(func() {})
+	g.background.Wait()
+	g.do(func() {})
+}
+
+// post queues fn to run on the Run goroutine without waiting for it. Commands
+// posted after the game stops are dropped.
+func (g *Game) post(fn func()) {
+	select {
+	case g.commands <- fn:
+	case <-g.stopped:
+	case <-g.ctx.Done():
+	}
+}
+
+// offLoop runs work on its own goroutine, for anything too slow to run on the
+// game loop such as a database query, and then runs the function work returns
+// on the Run goroutine. That function must check that the state work started
+// from still holds.
+func (g *Game) offLoop(work func() func()) {
+	g.background.Add(1)
+	go func() {
+		defer g.background.Done()
+		g.post(work())
+	}()
+}
+
+// after runs fn on the Run goroutine once d has passed.
+func (g *Game) after(d time.Duration, fn func()) clock.Timer {
+	return g.clock.AfterFunc(d, func() { g.post(fn) })
+}
+
+// signal queues a flow event. Run handles it after the current command
+// returns, so callers never block on the game loop.
+func (g *Game) signal(flow FlowEvent) {
+	g.flowQueue = append(g.flowQueue, flow)
+}
+
+// drainFlow handles queued flow events in order. It reports true once the
+// game has ended.
+func (g *Game) drainFlow() bool {
+	for len(g.flowQueue) > 0 {
+		flow := g.flowQueue[0]
+		g.flowQueue = g.flowQueue[1:]
+		g.FlowManager.HandleFlow(flow)
+		if flow == GameEnded {
+			return true
+		}
+	}
+	return false
+}
@@ -1,26 +1,172 @@
 package game
 
 import (
+	"errors"
 	"log"
 	"time"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
 )
 
-func (g *Game) HandleDisconnect(playerID string) {
-	g.Mu.Lock()
-	if player, exists := g.Players[playerID]; exists {
-		player.Connected = false
-	}
-	g.Mu.Unlock()
+const defaultReconnectGracePeriod = 30
+
+// departedSeatLifetime is how long a player removed after a disconnect can
+// still come back and reclaim their score.
+const departedSeatLifetime = 10 * time.Minute
+
+// ErrNoSeat means the player is not in the game and cannot be restored.
+var ErrNoSeat = errors.New("player has no seat in this game")
+
+// CheckSeat reports whether playerID can connect to this game, either because
+// they are still in it or because they left recently enough to be restored.
+// It returns ErrNoSeat if they cannot, and ErrGameFull if they left and the
+// game has filled up since.
+func (g *Game) CheckSeat(playerID string) error {
+	err := ErrNoSeat
+	g.query(func() {
+		if _, ok := g.Players[playerID]; ok {
+			err = nil
+		} else if _, ok := g.departedPlayers[playerID]; ok {
+			err = g.checkCapacity()
+		}
+	})
+	return err
+}
 
-	go func() {
-		time.Sleep(30 * time.Second)
-		g.Mu.Lock()
-		defer g.Mu.Unlock()
-		if player, exists := g.Players[playerID]; exists && !player.Connected {
-			g.RemovePlayer(playerID)
-			log.Println("Player removed due to disconnection:", playerID)
-			g.Messenger.BroadcastMessage([]byte("Player removed due to disconnection: " + playerID))
+// ConnectPlayer binds client to the player's seat. A player who was removed
+// after a disconnect is restored with their score and, if still free, their
+// color, as long as the game is not full. reconnected is false only for the
+// first connection after joining. The returned player is a copy.
+func (g *Game) ConnectPlayer(playerID, ip string, client shared.ClientInterface) (player *shared.Player, reconnected bool, err error) {
+	if !g.do(func() {
+		var p *shared.Player
+		if p, reconnected, err = g.connectPlayer(playerID, ip, client); p != nil {
+			player = clonePlayer(p)
+		}
+	}) {
+		return nil, false, ErrGameClosed
+	}
+	return player, reconnected, err
+}
 
+func (g *Game) connectPlayer(playerID, ip string, client shared.ClientInterface) (player *shared.Player, reconnected bool, err error) {
+	player, exists := g.Players[playerID]
+	if !exists {
+		player, exists = g.departedPlayers[playerID]
+		if !exists {
+			return nil, false, ErrNoSeat
 		}
-	}()
+		if err := g.checkCapacity(); err != nil {
+			log.Printf("connectPlayer: cannot restore player %s: %v", playerID, err)
+			return nil, false, err
+		}
+		delete(g.departedPlayers, playerID)
+		g.restorePlayer(player)
+	}
+
+	if timer, ok := g.disconnectTimers[playerID]; ok {
+		timer.Stop()
+		delete(g.disconnectTimers, playerID)
+	}
+
+	if player.Client != nil && player.Client != client {
+		player.Client.Close()
+	}
+
+	reconnected = !player.Pending
+	player.Pending = false
+	player.Connected = true
+	player.ConnectedAt = g.clock.Now()
+	player.IP = ip
+	player.Client = client
+	return player, reconnected, nil
+}
+
+// restorePlayer puts a departed player back at the end of the order.
+func (g *Game) restorePlayer(player *shared.Player) {
+	player.Color = g.takeColor(player.Color)
+	player.IsDrawing = false
+	player.IsGuessCorrect = false
+	g.Players[player.ID] = player
+	g.PlayerOrder = append(g.PlayerOrder, player.ID)
+	log.Printf("restorePlayer: restored player %s with score %d", player.ID, player.Score)
+}
+
+func (g *Game) reconnectGracePeriod() time.Duration {
+	seconds := g.Options.ReconnectGracePeriod
+	if seconds <= 0 {
+		seconds = defaultReconnectGracePeriod
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// handleDisconnect marks a player as disconnected and holds their seat for the
+// reconnect grace period. It also keeps the game moving: the host role moves
+// to a connected player, and a turn that can no longer finish normally ends.
+// A connection the player has already replaced by reconnecting is ignored.
+func (g *Game) handleDisconnect(playerID string, client shared.ClientInterface) {
+	player, exists := g.Players[playerID]
+	if !exists || player.Client != client {
+		return
+	}
+	player.Connected = false
+	player.ConnectedAt = time.Time{}
+	log.Printf("handleDisconnect: player %s disconnected", playerID)
+
+	if timer, ok := g.disconnectTimers[playerID]; ok {
+		timer.Stop()
+	}
+	g.disconnectTimers[playerID] = g.after(g.reconnectGracePeriod(), func() {
+		g.removeDisconnectedPlayer(playerID)
+	})
+
+	if player.IsHost {
+		g.migrateHost(playerID)
+	}
+
+	g.resolveTurnAfterDeparture(playerID)
+	g.broadcastGameState()
+}
+
+// resolveTurnAfterDeparture ends the current turn early when the player who
+// left was drawing, or when everyone still connected has already guessed.
+func (g *Game) resolveTurnAfterDeparture(playerID string) {
+	inProgress := g.Status == InProgress
+	isDrawer := g.Round.CurrentDrawerID == playerID
+	phase := g.Phase
+	allGuessed := phase == PhaseDrawing && g.CurrentTurn.allGuessedCorrectly(g.Players)
+
+	switch {
+	case !inProgress:
+	case isDrawer && phase == PhaseWordSelection:
+		log.Println("Drawer left while selecting a word, skipping turn")
+		g.cancelTimer("selectWordTimer")
+		g.signal(TurnEnded)
+	case isDrawer:
+		// Cancelling the turn timer ends the turn.
+		log.Println("Drawer left, ending turn early")
+		g.cancelTimer("turnTimer")
+	case allGuessed:
+		log.Println("All remaining players have guessed correctly!")
+		g.cancelTimer("turnTimer")
+	}
+}
+
+func (g *Game) removeDisconnectedPlayer(playerID string) {
+	player, exists := g.Players[playerID]
+	if !exists || player.Connected {
+		return
+	}
+	g.departedPlayers[playerID] = player
+	g.disconnectTimers[playerID] = g.after(departedSeatLifetime, func() {
+		delete(g.departedPlayers, playerID)
+		delete(g.disconnectTimers, playerID)
+	})
+
+	g.removePlayer(playerID)
+	log.Println("Player removed due to disconnection:", playerID)
+
+	g.broadcast(e.PlayerLeft, e.PlayerLeftMessage{PlayerID: playerID})
+	g.broadcastGameState()
 }
@@ -0,0 +1,145 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWordList(t *testing.T) {
+	words, err := shared.ParseWordList(`["Standup", " merge  conflict ", "STANDUP", ""]`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Standup", "merge conflict"}, words)
+
+	words, err = shared.ParseWordList("Standup\r\nmerge conflict, rubber duck\n\nstandup")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Standup", "merge conflict", "rubber duck"}, words)
+
+	_, err = shared.ParseWordList(`["unterminated"`)
+	assert.Error(t, err)
+}
+
+func TestCustomWordOptions(t *testing.T) {
+	options := shared.DefaultGameOptions()
+	assert.Equal(t, shared.WordSourceDefault, options.WordSource)
+
+	options = shared.GameOptions{CustomWords: []string{"standup"}}
+	options.ApplyDefaults()
+	assert.Equal(t, shared.WordSourceMixed, options.WordSource)
+	assert.NoError(t, options.Validate())
+
+	for _, tt := range []struct {
+		name   string
+		source string
+		words  []string
+	}{
+		{name: "too few for custom only", source: shared.WordSourceCustom, words: []string{"standup", "retro"}},
+		{name: "mixed without words", source: shared.WordSourceMixed},
+		{name: "too long", words: []string{"supercalifragilisticexpialidocious"}},
+		{name: "bad characters", words: []string{"<script>"}},
+		{name: "no letters", words: []string{"1234"}},
+		{name: "unknown source", source: "everything", words: []string{"standup"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			options := shared.GameOptions{WordSource: tt.source, CustomWords: tt.words}
+			options.ApplyDefaults()
+			var optionsErr *shared.OptionsError
+			require.ErrorAs(t, options.Validate(), &optionsErr)
+			assert.Len(t, optionsErr.Fields, 1)
+		})
+	}
+
+	options = shared.GameOptions{CustomWords: []string{"standup", "darn it"}}
+	options.ApplyDefaults()
+	var optionsErr *shared.OptionsError
+	require.ErrorAs(t, g.ValidateOptions(options, chat.NewBlocklist("darn")), &optionsErr)
+	assert.Contains(t, optionsErr.Fields["customWords"], "darn it")
+}
+
+func TestCustomOnlyWords(t *testing.T) {
+	options := gameOptions
+	options.WordSource = shared.WordSourceCustom
+	options.CustomWords = []string{"standup", "retro", "rubber duck", "merge conflict"}
+	sim := gametest.New(t, options)
+	sim.JoinN(2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+
+	offered := sim.Turn().SelectableWords
+	require.Len(t, offered, 3)
+	for _, word := range offered {
+		assert.Equal(t, g.CustomCategory, word.Category)
+		assert.Contains(t, options.CustomWords, word.Word)
+	}
+
+	drawer := sim.Turn().DrawerID
+	sim.Send(drawer, e.SelectWord, e.SelectWordPayload{WordID: offered[1].Id})
+	sim.RunUntil(func() bool { return sim.Turn().Drawing })
+	assert.Equal(t, offered[1], *sim.Turn().Word)
+
+	guesser := guessersOf(sim)[0]
+	sim.Send(guesser, e.PlayerGuess, e.PlayerGuessPayload{Guess: offered[1].Word})
+	assert.True(t, sim.Game.GetGameState().Turn.PlayersGuessedCorrectly[guesser])
+}
+
+func TestMixedWordsDrawFromOnePool(t *testing.T) {
+	options := gameOptions
+	options.WordSource = shared.WordSourceMixed
+	options.Categories = []string{"Shape"}
+	options.CustomWords = []string{"standup", "retro", "rubber duck", "merge conflict"}
+	sim := gametest.New(t, options)
+	sim.JoinN(2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+
+	// The two shapes and four custom words make a pool of six, so an offer
+	// and a reroll use up every one of them, whichever source each came from.
+	offered := sim.Turn().SelectableWords
+	sim.Send(sim.Turn().DrawerID, e.RerollWords, nil)
+	offered = append(offered, sim.Turn().SelectableWords...)
+
+	var words []string
+	for _, word := range offered {
+		words = append(words, word.Word)
+	}
+	assert.ElementsMatch(t, append([]string{"Circle", "Triangle"}, options.CustomWords...), words)
+}
+
+func TestUpdateOptionsWithWordList(t *testing.T) {
+	sim := newTestGame(t, 2)
+	options := gameOptions
+	options.WordSource = shared.WordSourceCustom
+
+	sim.Send(sim.Host(), e.UpdateOptions, e.UpdateOptionsPayload{
+		Options:  options,
+		WordList: "standup\nretro, rubber duck\nRetro",
+	})
+	assert.Equal(t, []string{"standup", "retro", "rubber duck"}, sim.Game.GetGameState().Options.CustomWords)
+
+	sim.Send(sim.Host(), e.UpdateOptions, e.UpdateOptionsPayload{Options: options, WordList: "standup"})
+	assert.Equal(t, e.CodeInvalidOptions, lastError(t, sim, sim.Host()).Code)
+}
+
+func TestCustomWordsHiddenFromGuests(t *testing.T) {
+	options := gameOptions
+	options.WordSource = shared.WordSourceCustom
+	options.CustomWords = []string{"standup", "retro", "rubber duck"}
+	sim := gametest.New(t, options)
+	sim.JoinN(2)
+
+	for _, id := range sim.Players {
+		sim.Send(id, e.GameState, nil)
+		state := stateSeenBy(t, sim, id)
+		if id == sim.Host() {
+			assert.Equal(t, options.CustomWords, state.Options.CustomWords)
+		} else {
+			assert.Empty(t, state.Options.CustomWords)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"regexp"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/utils"
+)
+
+const (
+	minBrushWidth     = 1
+	maxBrushWidth     = 64
+	maxPointsPerBatch = 128
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+var errNotDrawer = errors.New("player is not the current drawer")
+
+func validPoint(p e.Point) bool {
+	return p.X >= 0 && p.X <= 1 && p.Y >= 0 && p.Y <= 1
+}
+
+func validateStrokeBegin(pt e.StrokeBeginPayload) error {
+	if !colorPattern.MatchString(pt.Color) {
+		return errors.New("invalid color")
+	}
+	if pt.Width < minBrushWidth || pt.Width > maxBrushWidth {
+		return errors.New("invalid brush width")
+	}
+	if !validPoint(pt.Point) {
+		return errors.New("point out of bounds")
+	}
+	return nil
+}
+
+func validateStrokePoints(pt e.StrokePointsPayload) error {
+	if len(pt.Points) == 0 || len(pt.Points) > maxPointsPerBatch {
+		return errors.New("invalid point batch size")
+	}
+	for _, p := range pt.Points {
+		if !validPoint(p) {
+			return errors.New("point out of bounds")
+		}
+	}
+	return nil
+}
+
+func validateFill(pt e.FillPayload) error {
+	if !colorPattern.MatchString(pt.Color) {
+		return errors.New("invalid color")
+	}
+	if !validPoint(pt.Point) {
+		return errors.New("point out of bounds")
+	}
+	return nil
+}
+
+// canDraw reports whether playerID may currently paint on the canvas.
+func (g *Game) canDraw(playerID string) bool {
+	return g.Status == InProgress &&
+		g.Round.CurrentDrawerID == playerID &&
+		g.Phase == PhaseDrawing &&
+		g.CurrentTurn.WordToGuess != nil
+}
+
+// initDrawingEvents registers the handlers for the drawing protocol.
+func (g *Game) initDrawingEvents() {
+	g.RegisterGameEvent(e.StrokeBegin, func(playerID string, payload json.RawMessage) {
+		var pt e.StrokeBeginPayload
+		if !g.decode(playerID, e.StrokeBegin, payload, &pt) {
+			return
+		}
+		if err := validateStrokeBegin(pt); err != nil {
+			log.Printf("Rejected strokeBegin from %s: %v", playerID, err)
+			g.sendError(playerID, e.StrokeBegin, e.CodeInvalidPayload, err.Error())
+			return
+		}
+
+		if !g.canDraw(playerID) {
+			log.Printf("Rejected strokeBegin from %s: %v", playerID, errNotDrawer)
+			g.sendError(playerID, e.StrokeBegin, e.CodeNotYourTurn, "Only the drawer can draw")
+			return
+		}
+		id := g.Canvas.beginStroke(pt.Color, pt.Width, pt.Point)
+
+		g.broadcast(e.StrokeBegin, e.StrokeBeginMessage{
+			StrokeID: id,
+			Color:    pt.Color,
+			Width:    pt.Width,
+			Point:    pt.Point,
+		})
+	})
+
+	g.RegisterGameEvent(e.StrokePoints, func(playerID string, payload json.RawMessage) {
+		var pt e.StrokePointsPayload
+		if !g.decode(playerID, e.StrokePoints, payload, &pt) {
+			return
+		}
+		if err := validateStrokePoints(pt); err != nil {
+			log.Printf("Rejected strokePoints from %s: %v", playerID, err)
+			g.sendError(playerID, e.StrokePoints, e.CodeInvalidPayload, err.Error())
+			return
+		}
+
+		if !g.canDraw(playerID) {
+			log.Printf("Rejected strokePoints from %s: %v", playerID, errNotDrawer)
+			g.sendError(playerID, e.StrokePoints, e.CodeNotYourTurn, "Only the drawer can draw")
+			return
+		}
+		id, err := g.Canvas.appendPoints(pt.Points)
+		if err != nil {
+			log.Printf("Rejected strokePoints from %s: %v", playerID, err)
+			g.sendError(playerID, e.StrokePoints, e.CodeInvalidPayload, err.Error())
+			return
+		}
+
+		g.broadcast(e.StrokePoints, e.StrokePointsMessage{
+			StrokeID: id,
+			Points:   pt.Points,
+		})
+	})
+
+	g.RegisterGameEvent(e.StrokeEnd, func(playerID string, payload json.RawMessage) {
+		if !g.canDraw(playerID) {
+			log.Printf("Rejected strokeEnd from %s: %v", playerID, errNotDrawer)
+			g.sendError(playerID, e.StrokeEnd, e.CodeNotYourTurn, "Only the drawer can draw")
+			return
+		}
+		id, err := g.Canvas.endStroke()
+		if err != nil {
+			log.Printf("Rejected strokeEnd from %s: %v", playerID, err)
+			g.sendError(playerID, e.StrokeEnd, e.CodeInvalidPayload, err.Error())
+			return
+		}
+
+		g.broadcast(e.StrokeEnd, e.StrokeEndMessage{StrokeID: id})
+	})
+
+	g.RegisterGameEvent(e.Fill, func(playerID string, payload json.RawMessage) {
+		var pt e.FillPayload
+		if !g.decode(playerID, e.Fill, payload, &pt) {
+			return
+		}
+		if err := validateFill(pt); err != nil {
+			log.Printf("Rejected fill from %s: %v", playerID, err)
+			g.sendError(playerID, e.Fill, e.CodeInvalidPayload, err.Error())
+			return
+		}
+
+		if !g.canDraw(playerID) {
+			log.Printf("Rejected fill from %s: %v", playerID, errNotDrawer)
+			g.sendError(playerID, e.Fill, e.CodeNotYourTurn, "Only the drawer can draw")
+			return
+		}
+		id := g.Canvas.fill(pt.Color, pt.Point)
+
+		g.broadcast(e.Fill, e.FillMessage{
+			StrokeID: id,
+			Color:    pt.Color,
+			Point:    pt.Point,
+		})
+	})
+
+	g.RegisterGameEvent(e.Undo, func(playerID string, payload json.RawMessage) {
+		if !g.canDraw(playerID) {
+			log.Printf("Rejected undo from %s: %v", playerID, errNotDrawer)
+			g.sendError(playerID, e.Undo, e.CodeNotYourTurn, "Only the drawer can draw")
+			return
+		}
+		id, ok := g.Canvas.undo()
+		if !ok {
+			return
+		}
+
+		g.broadcast(e.Undo, e.UndoMessage{StrokeID: id})
+	})
+
+	g.RegisterGameEvent(e.ClearCanvas, func(playerID string, payload json.RawMessage) {
+		if !g.canDraw(playerID) {
+			log.Printf("Rejected clearCanvas from %s: %v", playerID, errNotDrawer)
+			g.sendError(playerID, e.ClearCanvas, e.CodeNotYourTurn, "Only the drawer can draw")
+			return
+		}
+		g.Canvas.clear()
+
+		g.broadcast(e.ClearCanvas, struct{}{})
+	})
+}
+
+// SendCanvasSnapshot sends the current drawing to a single player so they can
+// catch up after joining or reconnecting mid-turn.
+func (g *Game) SendCanvasSnapshot(playerID string) {
+	g.do(func() { g.sendCanvasSnapshot(playerID) })
+}
+
+func (g *Game) sendCanvasSnapshot(playerID string) {
+	snapshot := e.CanvasSnapshotMessage{Strokes: g.Canvas.Snapshot()}
+
+	b, err := utils.CreateMessage(e.CanvasSnapshot, snapshot)
+	if err != nil {
+		log.Println("error marshalling canvasSnapshot message:", err)
+		return
+	}
+	g.Messenger.SendToPlayer(playerID, b)
+}
@@ -2,40 +2,78 @@ package game
 
 import (
 	"context"
+	"math/rand"
 	"slices"
 	"sync"
 	"time"
 
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
 	m "github.com/Ajstraight619/pictionary-server/internal/messaging"
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
 )
 
+// Game holds the state of a single game. Once Run has started, the state
+// belongs to the Run goroutine; other goroutines go through the exported
+// methods, which run as commands on it.
 type Game struct {
 	lifecycle   GameLifecycle             `json:"-"`
-	Mu          sync.RWMutex              `json:"-"`
+	cleanupOnce sync.Once                 `json:"-"`
 	ID          string                    `json:"id"`
 	Players     map[string]*shared.Player `json:"players"`
 	PlayerOrder []string                  `json:"playerOrder"`
 	timers      map[string]*Timer         `json:"-"`
 	Options     shared.GameOptions        `json:"options"`
 	Status      Status                    `json:"status"`
-	FlowSignal  chan FlowEvent            `json:"-"`
-	CurrentTurn *Turn                     `json:"currentTurn"`
-	Round       *Round                    `json:"round"`
-	Messenger   m.Messenger               `json:"-"`
-	GameEvents  map[string]EventHandler   `json:"-"`
+	Phase       Phase                     `json:"phase"`
+	// phaseDeadline is when the current phase is due to end, or zero if it
+	// has no time limit.
+	phaseDeadline time.Time               `json:"-"`
+	commands      chan func()             `json:"-"`
+	flowQueue     []FlowEvent             `json:"-"`
+	stopped       chan struct{}           `json:"-"`
+	CurrentTurn   *Turn                   `json:"currentTurn"`
+	Canvas        *Canvas                 `json:"-"`
+	Round         *Round                  `json:"round"`
+	Messenger     m.Messenger             `json:"-"`
+	GameEvents    map[string]EventHandler `json:"-"`
+	// background counts work started with offLoop that has yet to post its
+	// result back.
+	background sync.WaitGroup `json:"-"`
+	// handling is the client event being handled, so errors can echo its
+	// request ID. handlingFailed records that it was rejected, and
+	// handlingDeferred that its handler will reply once it resumes.
+	handling         *e.GameEvent `json:"-"`
+	handlingFailed   bool         `json:"-"`
+	handlingDeferred bool         `json:"-"`
 	// SelectableWords []shared.Word             `json:"selectableWords"`
+	// UsedWords holds every word offered in this game, so that none is
+	// offered twice until the word bank runs out.
 	UsedWords       []shared.Word `json:"-"`
 	AvailableColors []string      `json:"-"`
+	// departedPlayers keeps players removed after a disconnect so a valid
+	// session can reclaim their score and color for a while.
+	departedPlayers map[string]*shared.Player `json:"-"`
+	// disconnectTimers remove a disconnected player once their grace period
+	// is over, and later forget them once their departed seat expires.
+	disconnectTimers map[string]clock.Timer `json:"-"`
+	bans             *BanList               `json:"-"`
+	chatLimiter      *chat.Limiter          `json:"-"`
+	blocklist        *chat.Blocklist        `json:"-"`
 	// isSelectingWord bool
 	TimerManager *TimerManager
 	WordSelector *WordSelector
 	FlowManager  *FlowManager
 	ctx          context.Context `json:"-"`
-	lastActivity time.Time       `json:"-"`
+	clock        clock.Clock     `json:"-"`
+	// rand makes the game's random choices. It is seeded from the clock, so
+	// a game on a fake clock plays out the same way every time.
+	rand         *rand.Rand `json:"-"`
+	lastActivity time.Time  `json:"-"`
 }
 
-func NewGame(ctx context.Context, id string, options shared.GameOptions, messenger m.Messenger, lifecycle GameLifecycle) *Game {
+func NewGame(ctx context.Context, id string, options shared.GameOptions, messenger m.Messenger, lifecycle GameLifecycle, clk clock.Clock) *Game {
 	game := &Game{
 		ID:          id,
 		lifecycle:   lifecycle,
@@ -44,16 +82,25 @@ func NewGame(ctx context.Context, id string, options shared.GameOptions, messeng
 		PlayerOrder: []string{},
 		Options:     options,
 		Status:      NotStarted,
-		FlowSignal:  make(chan FlowEvent, 1),
+		Phase:       PhaseLobby,
+		commands:    make(chan func(), 64),
+		stopped:     make(chan struct{}),
 		Messenger:   messenger,
 		GameEvents:  make(map[string]EventHandler),
+		Canvas:      NewCanvas(),
 		UsedWords:   []shared.Word{},
 		// SelectableWords: []shared.Word{},
-		AvailableColors: slices.Clone(defaultColors),
-		ctx:             ctx,
-		lastActivity:    time.Now(),
+		AvailableColors:  slices.Clone(defaultColors),
+		departedPlayers:  make(map[string]*shared.Player),
+		disconnectTimers: make(map[string]clock.Timer),
+		bans:             NewBanList(),
+		chatLimiter:      chat.NewLimiter(chatBurst, chatInterval),
+		ctx:              ctx,
+		clock:            clk,
+		rand:             rand.New(rand.NewSource(clk.Now().UnixNano())),
+		lastActivity:     clk.Now(),
 	}
-	game.TimerManager = NewTimerManager(game)
+	game.TimerManager = NewTimerManager(game, clk)
 	game.WordSelector = NewWordSelector(game)
 	game.FlowManager = NewFlowManager(game)
 	game.Round = InitRound()
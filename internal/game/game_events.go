@@ -2,120 +2,295 @@ package game
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"slices"
 
 	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
 	"github.com/Ajstraight619/pictionary-server/internal/utils"
 )
 
-type EventHandler func(json.RawMessage)
+// EventHandler handles a client event. playerID is the sender as stamped by
+// its connection.
+type EventHandler func(playerID string, payload json.RawMessage)
 
+// RegisterGameEvent adds a handler for eventType. Handlers run on the Run
+// goroutine, so they must be registered before Run starts.
 func (g *Game) RegisterGameEvent(eventType string, handler EventHandler) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
 	g.GameEvents[eventType] = handler
+}
+
+// hostOnlyEvents may only be sent by the current host.
+var hostOnlyEvents = map[string]bool{
+	e.StartTimer:    true,
+	e.StopTimer:     true,
+	e.TransferHost:  true,
+	e.KickPlayer:    true,
+	e.BanPlayer:     true,
+	e.UpdateOptions: true,
+}
 
+// eventPhases lists the phases in which an event is accepted. Events that are
+// not listed are accepted in any phase.
+var eventPhases = map[string][]Phase{
+	e.StartTimer:    {PhaseLobby},
+	e.StopTimer:     {PhaseCountdown},
+	e.UpdateOptions: {PhaseLobby},
+	e.SelectWord:    {PhaseWordSelection},
+	e.RerollWords:   {PhaseWordSelection},
+	e.PlayerGuess:   {PhaseDrawing},
+	e.StrokeBegin:   {PhaseDrawing},
+	e.StrokePoints:  {PhaseDrawing},
+	e.StrokeEnd:     {PhaseDrawing},
+	e.Fill:          {PhaseDrawing},
+	e.Undo:          {PhaseDrawing},
+	e.ClearCanvas:   {PhaseDrawing},
 }
 
 // InitGameEvents registers the default event handlers for a game.
 func (g *Game) InitGameEvents() {
+	g.initDrawingEvents()
 
-	g.RegisterGameEvent(e.StartTimer, func(payload json.RawMessage) {
+	g.RegisterGameEvent(e.StartTimer, func(playerID string, payload json.RawMessage) {
 		var pt e.StartTimerPayload
-		if err := json.Unmarshal(payload, &pt); err != nil {
-			log.Println("Error unmarshalling StartTimer payload:", err)
+		if !g.decode(playerID, e.StartTimer, payload, &pt) {
 			return
 		}
 		if pt.TimerType == "startGameCountdown" {
-			g.TimerManager.StartGameCountdown(pt.TimerType, 5)
+			duration := g.Options.StartCountdown
+			g.TimerManager.StartGameCountdown(pt.TimerType, duration)
 		}
 	})
 
-	g.RegisterGameEvent(e.StopTimer, func(payload json.RawMessage) {
+	g.RegisterGameEvent(e.StopTimer, func(playerID string, payload json.RawMessage) {
 		var pt e.StopTimerPayload
-		if err := json.Unmarshal(payload, &pt); err != nil {
-			log.Println("Error unmarshalling StopTimer payload:", err)
+		if !g.decode(playerID, e.StopTimer, payload, &pt) {
 			return
 		}
 
 		if pt.TimerType == "startGameCountdown" {
-			g.CancelTimer(pt.TimerType)
-			g.Mu.Lock()
+			// The game is about to start once the countdown has run out.
+			if _, running := g.timers[pt.TimerType]; !running {
+				g.sendError(playerID, e.StopTimer, e.CodeCountdownEnded, "The countdown has already finished")
+				return
+			}
+			g.cancelTimer(pt.TimerType)
 			g.Status = NotStarted
-			g.Mu.Unlock()
 		}
 	})
 
-	g.RegisterGameEvent(e.SelectWord, func(payload json.RawMessage) {
+	g.RegisterGameEvent(e.SelectWord, func(playerID string, payload json.RawMessage) {
 		var pt e.SelectWordPayload
-		if err := json.Unmarshal(payload, &pt); err != nil {
-			log.Println("Error unmarshalling SelectWord payload:", err)
+		if !g.decode(playerID, e.SelectWord, payload, &pt) {
 			return
 		}
+		g.selectWord(playerID, pt)
+	})
 
-		log.Printf("Word selected manually: %s", pt.Word.Word)
+	g.RegisterGameEvent(e.RerollWords, func(playerID string, _ json.RawMessage) {
+		g.rerollWords(playerID)
+	})
 
-		g.CancelTimer("selectWordTimer")
-		g.setIsSelectingWord(false)
-		g.setWord(&pt.Word)
-		log.Printf("Word selected manually: %s", pt.Word.Word)
+	g.RegisterGameEvent(e.GameState, func(playerID string, _ json.RawMessage) {
+		g.sendGameState(playerID)
+	})
 
-		selectWordPayload := map[string]interface{}{
-			"word":            g.CurrentTurn.WordToGuess,
-			"isSelectingWord": false,
+	g.RegisterGameEvent(e.PlayerGuess, func(playerID string, payload json.RawMessage) {
+		var pt e.PlayerGuessPayload
+		if !g.decode(playerID, e.PlayerGuess, payload, &pt) {
+			return
 		}
 
-		b, err := utils.CreateMessage("selectedWord", selectWordPayload)
-		if err != nil {
-			log.Println("error marshalling selectedWord message:", err)
+		guess, ok := g.acceptChat(playerID, e.PlayerGuess, pt.Guess)
+		if !ok {
 			return
 		}
-		currentDrawer := g.Round.GetCurrentDrawer(g.Players, g.PlayerOrder)
-		g.Messenger.SendToPlayer(currentDrawer.ID, b)
-		g.BroadcastGameState()
-		g.FlowSignal <- TurnStarted
+		g.handlePlayerGuess(playerID, e.PlayerGuess, guess)
 	})
 
-	g.RegisterGameEvent(e.GameState, func(payload json.RawMessage) {
-		var pt e.GameStatePayload
+	g.RegisterGameEvent(e.Chat, func(playerID string, payload json.RawMessage) {
+		var pt e.ChatPayload
+		if !g.decode(playerID, e.Chat, payload, &pt) {
+			return
+		}
 
-		if err := json.Unmarshal(payload, &pt); err != nil {
-			log.Println("Error unmarshalling GameState payload:", err)
+		g.handleChat(playerID, pt.Text)
+	})
+
+	g.RegisterGameEvent(e.TransferHost, func(playerID string, payload json.RawMessage) {
+		var pt e.TransferHostPayload
+		if !g.decode(playerID, e.TransferHost, payload, &pt) {
 			return
 		}
 
-		playerID := pt.PlayerID
+		if !g.transferHost(playerID, pt.PlayerID) {
+			log.Printf("Rejected transferHost from %s to %s", playerID, pt.PlayerID)
+			g.sendError(playerID, e.TransferHost, e.CodeInvalidTarget, "The host cannot be given to that player")
+		}
+	})
 
-		b, err := utils.CreateMessage("gameState", g.GetGameState())
-		if err != nil {
-			log.Println("error marshalling game state:", err)
+	g.RegisterGameEvent(e.KickPlayer, func(playerID string, payload json.RawMessage) {
+		var pt e.KickPlayerPayload
+		if !g.decode(playerID, e.KickPlayer, payload, &pt) {
 			return
 		}
 
-		g.Messenger.SendToPlayer(playerID, b)
+		if !g.kickPlayer(playerID, pt.PlayerID, pt.Reason, false) {
+			log.Printf("Rejected kickPlayer from %s for %s", playerID, pt.PlayerID)
+			g.sendError(playerID, e.KickPlayer, e.CodeInvalidTarget, "That player cannot be kicked")
+		}
 	})
 
-	g.RegisterGameEvent(e.PlayerGuess, func(payload json.RawMessage) {
-		var pt e.PlayerGuessPayload
+	g.RegisterGameEvent(e.BanPlayer, func(playerID string, payload json.RawMessage) {
+		var pt e.KickPlayerPayload
+		if !g.decode(playerID, e.BanPlayer, payload, &pt) {
+			return
+		}
+
+		if !g.kickPlayer(playerID, pt.PlayerID, pt.Reason, true) {
+			log.Printf("Rejected banPlayer from %s for %s", playerID, pt.PlayerID)
+			g.sendError(playerID, e.BanPlayer, e.CodeInvalidTarget, "That player cannot be banned")
+		}
+	})
 
-		if err := json.Unmarshal(payload, &pt); err != nil {
-			log.Println("Error unmarshalling PlayerGuess payload:", err)
+	g.RegisterGameEvent(e.UpdateOptions, func(playerID string, payload json.RawMessage) {
+		var pt e.UpdateOptionsPayload
+		if !g.decode(playerID, e.UpdateOptions, payload, &pt) {
 			return
 		}
 
-		g.handlePlayerGuess(pt.PlayerID, pt.Guess)
+		if pt.WordList != "" {
+			words, err := shared.ParseWordList(pt.WordList)
+			if err != nil {
+				g.sendError(playerID, e.UpdateOptions, e.CodeInvalidOptions, err.Error())
+				return
+			}
+			pt.Options.CustomWords = words
+		}
+
+		// Checking the categories queries the database, so validation runs
+		// off the game loop and the options are applied once it is done.
+		options := pt.Options
+		options.ApplyDefaults()
+		blocklist := g.blocklist
+		event := g.deferReply()
+		g.offLoop(func() func() {
+			err := ValidateOptions(options, blocklist)
+			return func() {
+				g.resume(event, func() {
+					if err == nil {
+						err = g.updateOptions(options)
+					}
+					if err != nil {
+						log.Printf("Rejected updateOptions from %s: %v", playerID, err)
+						g.sendError(playerID, e.UpdateOptions, e.CodeInvalidOptions, err.Error())
+					}
+				})
+			}
+		})
 	})
 
 }
 
 func (g *Game) handleExternalEvent(event e.GameEvent) {
-	g.Mu.RLock()
+	// Events can still be queued from a player who has since been kicked or
+	// banned; they no longer have a seat to act from.
+	if _, ok := g.Players[event.PlayerID]; !ok {
+		log.Printf("Dropped %s from %s: not in game", event.Type, event.PlayerID)
+		return
+	}
+
 	handler, exists := g.GameEvents[event.Type]
-	g.Mu.RUnlock()
+	allowed := !hostOnlyEvents[event.Type] || g.isHost(event.PlayerID)
+
+	g.handling = &event
+	g.handlingFailed = false
+	g.handlingDeferred = false
+	defer func() { g.handling = nil }()
+
+	if !exists {
+		g.sendError(event.PlayerID, event.Type, e.CodeUnknownEvent, fmt.Sprintf("Unknown event %q", event.Type))
+		return
+	}
+
+	if !allowed {
+		log.Printf("Rejected %s from %s: host only", event.Type, event.PlayerID)
+		g.sendError(event.PlayerID, event.Type, e.CodeNotHost, "Only the host can do that")
+		return
+	}
+
+	if phases, ok := eventPhases[event.Type]; ok && !slices.Contains(phases, g.Phase) {
+		log.Printf("Rejected %s from %s: not allowed during %s", event.Type, event.PlayerID, g.Phase)
+		g.sendError(event.PlayerID, event.Type, e.CodeWrongPhase, fmt.Sprintf("%s is not allowed during %s", event.Type, g.Phase))
+		return
+	}
+
+	// Handlers run inline on the Run goroutine, so events from a client are
+	// applied in the order it sent them.
+	handler(event.PlayerID, event.Payload)
+
+	if !g.handlingDeferred {
+		g.ack(event)
+	}
+}
+
+// ack confirms event to its sender unless it was rejected.
+func (g *Game) ack(event e.GameEvent) {
+	if !g.handlingFailed && event.RequestID != "" {
+		g.sendTo(event.PlayerID, e.Ack, e.AckPayload{Event: event.Type, RequestID: event.RequestID})
+	}
+}
+
+// deferReply holds back the ack for the event being handled, for handlers
+// that finish their work later. It returns the event for resume.
+func (g *Game) deferReply() e.GameEvent {
+	g.handlingDeferred = true
+	return *g.handling
+}
+
+// resume runs fn as the rest of the handler for event, so that errors echo
+// its request ID and it is acked if fn sends none.
+func (g *Game) resume(event e.GameEvent, fn func()) {
+	g.handling = &event
+	g.handlingFailed = false
+	defer func() { g.handling = nil }()
+	fn()
+	g.ack(event)
+}
+
+// decode unmarshals an event payload into v, telling the sender if it is
+// malformed.
+func (g *Game) decode(playerID, eventType string, payload json.RawMessage, v any) bool {
+	if err := json.Unmarshal(payload, v); err != nil {
+		log.Printf("Error unmarshalling %s payload: %v", eventType, err)
+		g.sendError(playerID, eventType, e.CodeInvalidPayload, "Malformed payload")
+		return false
+	}
+	return true
+}
+
+// sendError tells playerID that their event was rejected. If that event is
+// the one being handled, its request ID is echoed back and it is not acked.
+func (g *Game) sendError(playerID, eventType, code, message string) {
+	payload := e.ErrorPayload{
+		Event:   eventType,
+		Code:    code,
+		Message: message,
+	}
+	if ev := g.handling; ev != nil && ev.PlayerID == playerID && ev.Type == eventType {
+		payload.RequestID = ev.RequestID
+		g.handlingFailed = true
+	}
+	g.sendTo(playerID, e.Error, payload)
+}
 
-	if exists {
-		log.Printf("Dispatching custom handler for event type: %s", event.Type)
-		go handler(event.Payload)
+// sendTo sends a message to a single player.
+func (g *Game) sendTo(playerID, msgType string, payload any) {
+	b, err := utils.CreateMessage(msgType, payload)
+	if err != nil {
+		log.Printf("error marshalling %s message: %v", msgType, err)
 		return
 	}
+	g.Messenger.SendToPlayer(playerID, b)
 }
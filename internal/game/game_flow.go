@@ -40,61 +40,95 @@ func (fm *FlowManager) HandleFlow(flow FlowEvent) {
 }
 
 func (fm *FlowManager) handleGameStarted() {
-	fm.game.FlowSignal <- RoundStarted
+	if fm.game.Phase != PhaseCountdown {
+		log.Printf("Ignoring game start during %s", fm.game.Phase)
+		return
+	}
+	fm.game.signal(RoundStarted)
 }
 
 func (fm *FlowManager) handleGameEnded() {
-	fm.game.Mu.Lock()
+	fm.game.setPhase(PhaseGameOver, 0)
 	fm.game.Status = Finished
-	fm.game.Mu.Unlock()
-	fm.game.BroadcastGameState()
+	fm.game.broadcastPodium()
+	fm.game.broadcastGameState()
 	fm.game.cleanup()
 }
 
 func (fm *FlowManager) handleRoundStarted() {
-	fm.game.BroadcastGameState()
+	if fm.game.Phase != PhaseCountdown && fm.game.Phase != PhaseRoundResults {
+		log.Printf("Ignoring round start during %s", fm.game.Phase)
+		return
+	}
+	fm.game.broadcastGameState()
 	fm.game.Round.Start(fm.game)
 }
 
+// handleRoundEnded shows the standings, then starts the next round after a
+// short pause. After the last round the game ends straight away.
 func (fm *FlowManager) handleRoundEnded() {
-	log.Printf("Round %d ended", fm.game.Round.Count)
-	if fm.game.Round.Count == fm.game.Options.RoundLimit {
+	g := fm.game
+	lastRound := g.Round.Count == g.Options.RoundLimit
+	duration := roundResultsDuration
+	if lastRound {
+		duration = 0
+	}
+	if !g.setPhase(PhaseRoundResults, duration) {
+		return
+	}
+	log.Printf("Round %d ended", g.Round.Count)
+	g.broadcastRoundResults()
+	if lastRound {
 		log.Println("Game over!")
-		fm.game.FlowSignal <- GameEnded
+		g.signal(GameEnded)
 		return
 	}
-	fm.game.Round.Next(fm.game)
+	g.broadcastGameState()
+	g.after(roundResultsDuration, func() {
+		if g.Phase == PhaseRoundResults {
+			g.Round.Next(g)
+		}
+	})
 }
 
+// handleTurnStarted starts word selection for a new turn, or the drawing
+// phase once a word has been chosen.
 func (fm *FlowManager) handleTurnStarted() {
-	fm.game.BroadcastGameState()
-	turn := fm.game.CurrentTurn
+	g := fm.game
+	turn := g.CurrentTurn
 
 	log.Printf("Turn started: %v", turn)
 
-	switch turn.Phase {
+	switch g.Phase {
+	case PhaseCountdown, PhaseTurnResults, PhaseRoundResults:
+		// The word selection timer starts after a one second pause.
+		if !g.setPhase(PhaseWordSelection, seconds(g.Options.WordSelectTimeLimit+1)) {
+			return
+		}
+		log.Println("No word selected. Initiating word selection...")
+		g.WordSelector.SelectWord()
 	case PhaseWordSelection:
 		if turn.WordToGuess == nil {
-			log.Println("No word selected. Initiating word selection...")
-			fm.game.WordSelector.SelectWord()
+			log.Println("Ignoring turn start: no word selected yet")
 			return
 		}
-		log.Println("Word selected. Switching to drawing phase.")
-		turn.Phase = PhaseDrawing
-		fm.handleTurnStarted()
-	case PhaseDrawing:
-		drawer := fm.game.Round.GetCurrentDrawer(fm.game.Players, fm.game.PlayerOrder)
+		drawer := g.Round.GetCurrentDrawer(g.Players)
 		if drawer == nil {
 			log.Println("No current drawer found; cannot start turn.")
 			return
 		}
+		g.setPhase(PhaseDrawing, seconds(g.Options.TurnTimeLimit))
+		g.broadcastGameState()
 		log.Println("Starting drawing phase for drawer", drawer.ID)
-		turn.Start(fm.game, drawer.ID)
+		turn.Start(g, drawer.ID)
 	default:
-		log.Println("Unknown turn phase encountered.")
+		log.Printf("Ignoring turn start during %s", g.Phase)
 	}
 }
 
 func (fm *FlowManager) handleTurnEnded() {
+	if !fm.game.setPhase(PhaseTurnResults, turnResultsDuration) {
+		return
+	}
 	fm.game.CurrentTurn.End(fm.game)
 }
@@ -4,38 +4,36 @@ import (
 	"log"
 	"time"
 
-	"github.com/Ajstraight619/pictionary-server/internal/utils"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
 )
 
 type GameLifecycle interface {
 	OnGameEnded(gameID string)
 }
 
+// cleanup runs once, either when the game ends or when it is shut down.
 func (g *Game) cleanup() {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+	g.cleanupOnce.Do(g.doCleanup)
+}
 
-	// Cancel all timers
+func (g *Game) doCleanup() {
+	// Stop all timers without running their cancel callbacks.
 	for _, timer := range g.timers {
-		timer.Cancel()
+		timer.stop()
 	}
-
-	// Close channels safely
-	if g.FlowSignal != nil {
-		close(g.FlowSignal)
+	for _, timer := range g.disconnectTimers {
+		timer.Stop()
 	}
+	clear(g.disconnectTimers)
+	clear(g.departedPlayers)
 
 	// Clear all game state
 	g.Status = Finished
+	g.Phase = PhaseGameOver
+	g.phaseDeadline = time.Time{}
 
 	// Notify all players BEFORE we clear state
-	message := map[string]interface{}{
-		"type":    "gameEnded",
-		"message": "Game has been terminated",
-	}
-	if b, err := utils.CreateMessage("gameEnded", message); err == nil {
-		g.Messenger.BroadcastMessage(b)
-	}
+	g.broadcast(e.GameEnded, e.GameEndedMessage{Message: "Game has been terminated"})
 
 	// Notify lifecycle handler that game is done
 	if g.lifecycle != nil {
@@ -43,26 +41,35 @@ func (g *Game) cleanup() {
 	}
 }
 
+// Run is the game loop. It is the only goroutine that touches game state:
+// commands, client events and disconnects are handled one at a time, and any
+// flow events they raise are handled before the next one is read.
 func (g *Game) Run() {
+	defer close(g.stopped)
 	defer g.cleanup()
 
 	for {
 		select {
-		case flow := <-g.FlowSignal:
-			g.FlowManager.HandleFlow(flow)
+		case cmd := <-g.commands:
+			cmd()
 		case event := <-g.Messenger.GameEventChannel():
 			g.handleExternalEvent(event)
+		case disconnect := <-g.Messenger.DisconnectChannel():
+			g.handleDisconnect(disconnect.PlayerID, disconnect.Client)
 		case <-g.ctx.Done():
 			// The game is being shut down
 			log.Printf("Game %s is shutting down...", g.ID)
 			return
 		}
+		if g.drainFlow() {
+			return
+		}
 	}
 }
 
-func (g *Game) Start() {
-	g.BroadcastGameState()
-	time.AfterFunc(2*time.Second, func() {
-		g.FlowSignal <- GameStarted
+func (g *Game) start() {
+	g.broadcastGameState()
+	g.after(2*time.Second, func() {
+		g.signal(GameStarted)
 	})
 }
@@ -1,192 +1,228 @@
 package game_test
 
-//
-// import (
-// 	"net/http"
-// 	"net/http/httptest"
-// 	"strings"
-// 	"testing"
-// 	"time"
-//
-// 	e "github.com/Ajstraight619/pictionary-server/internal/events"
-// 	g "github.com/Ajstraight619/pictionary-server/internal/game"
-// 	"github.com/Ajstraight619/pictionary-server/internal/shared"
-// 	"github.com/Ajstraight619/pictionary-server/internal/ws"
-// 	"github.com/gorilla/websocket"
-// )
-//
-// var hubs = ws.NewHubs()
-//
-// var gameOptions = shared.GameOptions{
-// 	MaxPlayers:          4,
-// 	TurnTimeLimit:       60,
-// 	RoundLimit:          3,
-// 	WordSelectTimeLimit: 30,
-// }
-//
-// func echoHandler(w http.ResponseWriter, r *http.Request) {
-// 	upgrader := websocket.Upgrader{
-// 		// For testing, we allow all origins.
-// 		CheckOrigin: func(r *http.Request) bool { return true },
-// 	}
-// 	conn, err := upgrader.Upgrade(w, r, nil)
-// 	if err != nil {
-// 		http.Error(w, "Upgrade error", http.StatusBadRequest)
-// 		return
-// 	}
-// 	defer conn.Close()
-//
-// 	// In this simple test, just keep reading messages in a loop.
-// 	for {
-// 		mt, msg, err := conn.ReadMessage()
-// 		if err != nil {
-// 			break
-// 		}
-// 		// Echo back the same message.
-// 		if err := conn.WriteMessage(mt, msg); err != nil {
-// 			break
-// 		}
-// 	}
-// }
-//
-// func dialWS(wsURL string) (*websocket.Conn, error) {
-// 	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
-// 	return conn, err
-// }
-//
-// func createTestHubAndClients(t *testing.T, playerIDs []string) (*ws.Hub, []*ws.Client, func()) {
-// 	// Create a test HTTP server.
-// 	server := httptest.NewServer(http.HandlerFunc(echoHandler))
-// 	// Convert the server URL to a WebSocket URL.
-// 	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
-//
-// 	// Create a Hub and run it.
-// 	hub := ws.NewHub()
-// 	go hub.Run()
-//
-// 	var clients []*ws.Client
-// 	// For each player, dial the WebSocket and create a client.
-// 	for _, pid := range playerIDs {
-// 		conn, err := dialWS(wsURL)
-// 		if err != nil {
-// 			t.Fatalf("Dial failed for player %s: %v", pid, err)
-// 		}
-// 		client := ws.NewClient(hub, conn, pid)
-// 		clients = append(clients, client)
-// 		// Register the client.
-// 		hub.Register <- client
-//
-// 		// Start the client's Read and Write loops.
-// 		go client.Read()
-// 		go client.Write()
-// 	}
-//
-// 	// Teardown function to close all client connections and the test server.
-// 	teardown := func() {
-// 		for _, client := range clients {
-// 			client.Conn.Close()
-// 		}
-// 		server.Close()
-// 	}
-//
-// 	return hub, clients, teardown
-// }
-//
-// func createPlayers() []*shared.Player {
-// 	players := []*shared.Player{
-// 		{
-// 			ID:       "player1",
-// 			Username: "player1",
-// 			IsHost:   true,
-// 		},
-// 		{
-// 			ID:       "player2",
-// 			Username: "player2",
-// 		},
-// 		{
-// 			ID:       "player3",
-// 			Username: "player3",
-// 		},
-// 		{
-// 			ID:       "player4",
-// 			Username: "player4",
-// 		},
-// 	}
-//
-// 	return players
-// }
-//
-// // func TestGame(t *testing.T) {
-// //
-// // 	hub := ws.NewHub()
-// // 	hubs.AddHub("testHub", hub)
-// //
-// // 	go hub.Run()
-// //
-// // 	game := g.NewGame("testGame", gameOptions, hub)
-// //
-// // 	for _, player := range createPlayers() {
-// // 		p := g.NewPlayer(player.ID, player.Username, player.IsHost)
-// // 		game.AddPlayer(p)
-// // 		fmt.Println(p)
-// // 	}
-// //
-// // }
-//
-// func TestGameWithRealClients(t *testing.T) {
-// 	// Define some player IDs.
-// 	playerIDs := []string{"player1", "player2", "player3", "player4"}
-//
-// 	// Create Hub and Clients.
-// 	hub, clients, teardown := createTestHubAndClients(t, playerIDs)
-// 	defer teardown()
-//
-// 	// Create game options.
-// 	gameOptions := shared.GameOptions{
-// 		MaxPlayers:          4,
-// 		TurnTimeLimit:       60,
-// 		RoundLimit:          3,
-// 		WordSelectTimeLimit: 30,
-// 	}
-//
-// 	// Create a new Game using the Hub as the Messenger.
-// 	game := g.NewGame("testGame", gameOptions, hub)
-//
-// 	// Add players to the game.
-// 	for _, pid := range playerIDs {
-// 		// Create a new player.
-// 		p := g.NewPlayer(pid, pid, pid == "player1")
-// 		game.AddPlayer(p)
-// 		t.Logf("Added player: %s", p)
-// 	}
-//
-// 	// Now simulate a broadcast from the Hub.
-// 	testMsg := []byte(`{"type":"test","payload":"hello from hub"}`)
-// 	hub.BroadcastMessage(testMsg)
-//
-// 	// Wait briefly to allow messages to propagate.
-// 	time.Sleep(200 * time.Millisecond)
-//
-// 	// Check that each client received the broadcast message.
-// 	for _, client := range clients {
-// 		select {
-// 		case msg := <-client.Send:
-// 			t.Logf("Client %s received: %s", client.PlayerID, string(msg))
-// 		case <-time.After(1 * time.Second):
-// 			t.Errorf("Client %s did not receive any message", client.PlayerID)
-// 		}
-// 	}
-//
-// 	// Optionally, simulate sending a GameEvent.
-// 	// For example, let's send a dummy GameEvent:
-// 	dummyEvent := e.GameEvent{
-// 		Type:    "testEvent",
-// 		Payload: []byte(`{"info": "event payload"}`),
-// 	}
-// 	hub.GameEvents <- dummyEvent
-//
-// 	// Let the hub process the event.
-// 	time.Sleep(100 * time.Millisecond)
-// 	// (In a full integration test, you'd have additional logic in your Game
-// 	// or Hub to process and broadcast responses to such events.)
-// }
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+
+	if err := gametest.UseMemoryDB(gametest.DefaultWords); err != nil {
+		log.Fatalf("Failed to seed words: %v", err)
+	}
+
+	os.Exit(m.Run())
+}
+
+var gameOptions = shared.GameOptions{
+	MaxPlayers:          4,
+	TurnTimeLimit:       10,
+	RoundLimit:          2,
+	WordSelectTimeLimit: 5,
+	StartCountdown:      3,
+}
+
+// newTestGame starts a simulated game with numPlayers connected players. The
+// first player is the host.
+func newTestGame(t *testing.T, numPlayers int) *gametest.Simulator {
+	t.Helper()
+	sim := gametest.New(t, gameOptions)
+	sim.JoinN(numPlayers)
+	return sim
+}
+
+func TestAddPlayerRespectsMaxPlayers(t *testing.T) {
+	game := newTestGame(t, gameOptions.MaxPlayers).Game
+
+	err := game.AddPlayer(game.NewPlayer("extra", "extra", false))
+	assert.ErrorIs(t, err, g.ErrGameFull)
+	assert.Len(t, game.GetGameState().PlayerOrder, gameOptions.MaxPlayers)
+}
+
+func TestAddPlayerAssignsUniqueColors(t *testing.T) {
+	game := newTestGame(t, 4).Game
+
+	seen := make(map[string]bool)
+	for _, p := range game.GetGameState().Players {
+		assert.False(t, seen[p.Color], "color %s assigned twice", p.Color)
+		seen[p.Color] = true
+	}
+}
+
+func TestRemovePlayerMigratesHost(t *testing.T) {
+	game := newTestGame(t, 3).Game
+
+	game.RemovePlayer("player0")
+
+	assert.Nil(t, game.GetPlayerByID("player0"))
+	assert.True(t, game.GetPlayerByID("player1").IsHost)
+	assert.False(t, game.GetPlayerByID("player2").IsHost)
+}
+
+func TestStaleDisconnectAfterReconnect(t *testing.T) {
+	sim := newTestGame(t, 3)
+	host := sim.Host()
+	old := sim.Client(host)
+	sim.Reconnect(host)
+
+	// The replaced connection only reports closing once the new one is in.
+	sim.DisconnectClient(host, old)
+	player := sim.Game.GetPlayerByID(host)
+	assert.True(t, player.Connected)
+	assert.True(t, player.IsHost)
+
+	sim.Disconnect(host)
+	player = sim.Game.GetPlayerByID(host)
+	assert.False(t, player.Connected)
+	assert.False(t, player.IsHost)
+}
+
+func TestDisconnectedPlayersDoNotDraw(t *testing.T) {
+	options := gameOptions
+	options.RoundLimit = 1
+	options.ReconnectGracePeriod = 300
+	sim := gametest.New(t, options)
+	first, away, last := sim.Join("first"), sim.Join("away"), sim.Join("last")
+
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+	require.Equal(t, first, sim.Turn().DrawerID)
+
+	// The drawer leaving ends their turn straight away, and a player waiting
+	// to reconnect is passed over.
+	sim.Disconnect(away)
+	sim.Disconnect(first)
+	assert.Equal(t, g.PhaseTurnResults, sim.Phase())
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseWordSelection })
+	assert.Equal(t, last, sim.Turn().DrawerID)
+
+	// Once everyone still connected has drawn, the round, and with it the
+	// game, is over.
+	sim.RunUntil(sim.Finished)
+	var drawers []string
+	for _, msg := range messagesOf(sim, e.DrawingPlayerChanged) {
+		var changed e.DrawingPlayerChangedMessage
+		require.NoError(t, json.Unmarshal(msg.Payload, &changed))
+		drawers = append(drawers, changed.Player.ID)
+	}
+	assert.Equal(t, []string{first, last}, drawers)
+}
+
+func TestReconnectRespectsMaxPlayers(t *testing.T) {
+	options := gameOptions
+	options.MaxPlayers = 2
+	options.ReconnectGracePeriod = 5
+	sim := gametest.New(t, options)
+	_, away := sim.Join("host"), sim.Join("away")
+
+	sim.Disconnect(away)
+	sim.RunUntil(func() bool { return sim.Game.GetPlayerByID(away) == nil })
+	require.NoError(t, sim.Game.CheckSeat(away), "the player may come back")
+
+	// Someone takes the seat while they are away.
+	newcomer := sim.Join("newcomer")
+	assert.ErrorIs(t, sim.Game.CheckSeat(away), g.ErrGameFull)
+	_, _, err := sim.Game.ConnectPlayer(away, "", nil)
+	assert.ErrorIs(t, err, g.ErrGameFull)
+	assert.Len(t, sim.Game.GetGameState().PlayerOrder, 2)
+
+	sim.Game.RemovePlayer(newcomer)
+	sim.Reconnect(away)
+	assert.Equal(t, []string{sim.Host(), away}, sim.Game.GetGameState().PlayerOrder)
+}
+
+func TestDepartedSeatsExpire(t *testing.T) {
+	options := gameOptions
+	options.ReconnectGracePeriod = 5
+	sim := gametest.New(t, options)
+	_, away := sim.Join("host"), sim.Join("away")
+
+	sim.Disconnect(away)
+	sim.RunUntil(func() bool { return sim.Game.GetPlayerByID(away) == nil })
+	require.NoError(t, sim.Game.CheckSeat(away))
+	removedAt := sim.Clock.Now()
+	sim.RunUntil(func() bool { return sim.Game.CheckSeat(away) != nil })
+	assert.ErrorIs(t, sim.Game.CheckSeat(away), g.ErrNoSeat)
+	assert.GreaterOrEqual(t, sim.Clock.Now().Sub(removedAt), 10*time.Minute)
+}
+
+// TestConcurrentAccess drives one game from many goroutines at once while its
+// timers run. It is meant for the race detector: every state change has to go
+// through the Run goroutine.
+func TestConcurrentAccess(t *testing.T) {
+	sim := newTestGame(t, 1)
+	game := sim.Game
+	sim.StartGame()
+
+	var wg sync.WaitGroup
+	for i := 1; i < gameOptions.MaxPlayers; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			player := game.NewPlayer(id, id, false)
+			player.Pending = true
+			assert.NoError(t, game.AddPlayer(player))
+			game.ConnectPlayer(id, "", nil)
+
+			for j := 0; j < 20; j++ {
+				state := game.GetGameState()
+				assert.NotEmpty(t, state.Players)
+				game.BroadcastGameState()
+				game.SendCanvasSnapshot(id)
+				assert.NoError(t, sim.Messenger.Inject(id, e.PlayerGuess, e.PlayerGuessPayload{Guess: "guess"}))
+				assert.NoError(t, sim.Messenger.Inject(id, e.StrokeEnd, struct{}{}))
+			}
+		}(fmt.Sprintf("guest%d", i))
+	}
+	for step := 0; step < 100 && game.GetGameState().Status != g.InProgress; step++ {
+		sim.Step()
+	}
+	wg.Wait()
+
+	state := game.GetGameState()
+	assert.Len(t, state.Players, gameOptions.MaxPlayers)
+	assert.Equal(t, g.InProgress, state.Status)
+}
+
+func TestRandomChoicesFollowTheClock(t *testing.T) {
+	play := func(t *testing.T, options shared.GameOptions) []string {
+		sim := gametest.New(t, options)
+		sim.JoinN(2)
+		sim.StartGame()
+		sim.RunUntil(func() bool { return sim.Phase() == g.PhaseTurnResults })
+
+		var choices []string
+		for _, msg := range sim.Messenger.Messages() {
+			if msg.Type == e.OpenSelectWordModal || msg.Type == e.SelectedWord || msg.Type == e.RevealedLetter {
+				choices = append(choices, string(msg.Payload))
+			}
+		}
+		return choices
+	}
+
+	custom := gameOptions
+	custom.WordSource = shared.WordSourceCustom
+	custom.CustomWords = []string{"standup", "retrospective", "rubber duck", "merge conflict"}
+
+	for name, options := range map[string]shared.GameOptions{"bank": gameOptions, "custom": custom} {
+		t.Run(name, func(t *testing.T) {
+			choices := play(t, options)
+			assert.NotEmpty(t, choices)
+			assert.Equal(t, choices, play(t, options), "games on the same clock make the same random choices")
+		})
+	}
+}
@@ -0,0 +1,302 @@
+// Package gametest scripts whole games against an in-memory messenger and a
+// fake clock.
+package gametest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+	"github.com/Ajstraight619/pictionary-server/internal/db"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/messaging/messagingtest"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+)
+
+const (
+	// MaxSteps bounds how many simulated seconds a game may take before the
+	// simulator gives up.
+	MaxSteps = 2000
+	// settleTimeout is how long, in real time, to wait for the game at all.
+	settleTimeout = 2 * time.Second
+)
+
+// DefaultWords is a small word list covering a few categories, with two
+// words at each difficulty.
+var DefaultWords = []shared.Word{
+	{Word: "Ants", Category: "Animals", Difficulty: shared.DifficultyEasy},
+	{Word: "Spider", Category: "Animals", Difficulty: shared.DifficultyMedium},
+	{Word: "Tennis", Category: "Sports", Difficulty: shared.DifficultyMedium},
+	{Word: "Hockey", Category: "Sports", Difficulty: shared.DifficultyHard},
+	{Word: "Circle", Category: "Shape", Difficulty: shared.DifficultyEasy},
+	{Word: "Triangle", Category: "Shape", Difficulty: shared.DifficultyHard},
+}
+
+// UseMemoryDB points the db package at a fresh in-memory database seeded with
+// words. Call it from TestMain.
+func UseMemoryDB(words []shared.Word) error {
+	db.InitDB("file::memory:?cache=shared")
+	if err := db.MigrateWords(); err != nil {
+		return err
+	}
+	if err := db.DB.Where("1 = 1").Delete(&shared.Word{}).Error; err != nil {
+		return err
+	}
+	defer db.InvalidateWordIndex()
+	return db.DB.Create(&words).Error
+}
+
+// Simulator runs a single game on a fake clock. Players are connected with
+// stub clients and act by injecting events through the recorder.
+type Simulator struct {
+	t         testing.TB
+	Game      *game.Game
+	Messenger *messagingtest.Recorder
+	Clock     *clock.Fake
+	Players   []string
+	clients   map[string]*stubClient
+	done      chan struct{}
+}
+
+// New creates a game and starts its loop. The game is shut down when the
+// test finishes.
+func New(t testing.TB, options shared.GameOptions) *Simulator {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Simulator{
+		t:         t,
+		Messenger: messagingtest.NewRecorder(),
+		Clock:     clock.NewFake(time.Unix(0, 0)),
+		clients:   make(map[string]*stubClient),
+		done:      make(chan struct{}),
+	}
+	s.Game = game.NewGame(ctx, "sim", options, s.Messenger, nil, s.Clock)
+	s.Game.InitGameEvents()
+
+	go func() {
+		defer close(s.done)
+		s.Game.Run()
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-s.done
+	})
+	return s
+}
+
+// Join adds and connects a player. The first player to join is the host.
+func (s *Simulator) Join(username string) string {
+	s.t.Helper()
+	id := fmt.Sprintf("player%d", len(s.Players))
+	player := s.Game.NewPlayer(id, username, len(s.Players) == 0)
+	player.Pending = true
+	if err := s.Game.AddPlayer(player); err != nil {
+		s.t.Fatalf("Join(%s): %v", username, err)
+	}
+	s.Players = append(s.Players, id)
+	s.Reconnect(id)
+	return id
+}
+
+// Reconnect connects playerID again with a new client, replacing the one
+// they had.
+func (s *Simulator) Reconnect(playerID string) {
+	s.t.Helper()
+	s.ReconnectFrom(playerID, "")
+}
+
+// ReconnectFrom is Reconnect with the connection coming from ip.
+func (s *Simulator) ReconnectFrom(playerID, ip string) {
+	s.t.Helper()
+	client := &stubClient{}
+	if _, _, err := s.Game.ConnectPlayer(playerID, ip, client); err != nil {
+		s.t.Fatalf("Reconnect(%s): %v", playerID, err)
+	}
+	s.clients[playerID] = client
+}
+
+// JoinN adds n players named after their join order.
+func (s *Simulator) JoinN(n int) []string {
+	s.t.Helper()
+	for i := 0; i < n; i++ {
+		s.Join(fmt.Sprintf("player %d", len(s.Players)))
+	}
+	return s.Players
+}
+
+// Host returns the ID of the first player who joined.
+func (s *Simulator) Host() string {
+	return s.Players[0]
+}
+
+// Send injects an event from playerID and waits for the game to react.
+func (s *Simulator) Send(playerID, eventType string, payload any) {
+	s.t.Helper()
+	if err := s.Messenger.Inject(playerID, eventType, payload); err != nil {
+		s.t.Fatalf("Send(%s): %v", eventType, err)
+	}
+	s.Settle()
+}
+
+// Disconnect reports playerID's current connection as closed and waits for
+// the game to react.
+func (s *Simulator) Disconnect(playerID string) {
+	s.t.Helper()
+	s.DisconnectClient(playerID, s.clients[playerID])
+}
+
+// DisconnectClient reports one of playerID's connections as closed, which
+// may be one they have since replaced, and waits for the game to react.
+func (s *Simulator) DisconnectClient(playerID string, client shared.ClientInterface) {
+	s.t.Helper()
+	s.Messenger.Disconnect(playerID, client)
+	s.Settle()
+}
+
+// Client returns playerID's current connection.
+func (s *Simulator) Client(playerID string) shared.ClientInterface {
+	return s.clients[playerID]
+}
+
+// CloseFrame returns the close code and reason the game sent on playerID's
+// current connection. The code is zero if none was sent.
+func (s *Simulator) CloseFrame(playerID string) (int, string) {
+	client := s.clients[playerID]
+	return client.closeCode, client.closeReason
+}
+
+// StartGame has the host start the pre-game countdown.
+func (s *Simulator) StartGame() {
+	s.Send(s.Host(), e.StartTimer, e.StartTimerPayload{TimerType: "startGameCountdown"})
+}
+
+// Finished reports whether the game loop has exited.
+func (s *Simulator) Finished() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Step advances the clock by one second once the game is waiting on it.
+func (s *Simulator) Step() {
+	s.t.Helper()
+	s.waitFor(func() bool { return s.Finished() || s.Clock.Waiters() > 0 })
+	s.Clock.Advance(time.Second)
+	s.Settle()
+}
+
+// Settle waits until the game has handled every event injected so far and
+// everything that came due on the clock. The game receives injected events
+// in order, and a drained channel means the last one is being handled, so a
+// barrier pushed through the command queue afterwards runs once it is done.
+func (s *Simulator) Settle() {
+	s.t.Helper()
+	s.waitFor(func() bool { return s.Finished() || s.Messenger.Pending() == 0 })
+	s.Game.Sync()
+}
+
+// RunUntil steps the clock until cond holds.
+func (s *Simulator) RunUntil(cond func() bool) {
+	s.t.Helper()
+	for step := 0; !cond(); step++ {
+		if step > MaxSteps {
+			s.t.Fatalf("condition not met within %d simulated seconds", MaxSteps)
+		}
+		if s.Finished() {
+			s.t.Fatal("game ended before condition was met")
+		}
+		s.Step()
+	}
+}
+
+// PlayToEnd steps the clock until the game ends. onStep, if set, runs before
+// every tick so tests can act as players.
+func (s *Simulator) PlayToEnd(onStep func()) {
+	s.t.Helper()
+	for step := 0; !s.Finished(); step++ {
+		if step > MaxSteps {
+			s.t.Fatalf("game did not finish within %d simulated seconds", MaxSteps)
+		}
+		if onStep != nil {
+			onStep()
+		}
+		s.Step()
+	}
+}
+
+// Turn describes the current turn as seen by the test.
+type Turn struct {
+	DrawerID        string
+	Word            *shared.Word
+	SelectableWords []shared.Word
+	Phase           game.Phase
+	Drawing         bool
+}
+
+// Turn returns the current turn.
+func (s *Simulator) Turn() Turn {
+	state := s.Game.GetGameState()
+	turn := state.Turn
+	return Turn{
+		DrawerID:        state.CurrentDrawerID,
+		Word:            turn.WordToGuess,
+		SelectableWords: turn.SelectableWords,
+		Phase:           state.Phase,
+		Drawing:         state.Phase == game.PhaseDrawing,
+	}
+}
+
+// Phase returns the phase the game is in.
+func (s *Simulator) Phase() game.Phase {
+	return s.Game.GetGameState().Phase
+}
+
+// GuessAll has every connected guesser guess the current word correctly.
+func (s *Simulator) GuessAll() {
+	s.t.Helper()
+	turn := s.Turn()
+	if turn.Word == nil {
+		s.t.Fatal("GuessAll: no word to guess")
+	}
+	for _, id := range s.Players {
+		if id == turn.DrawerID || s.Game.GetPlayerByID(id) == nil {
+			continue
+		}
+		s.Send(id, e.PlayerGuess, e.PlayerGuessPayload{Guess: turn.Word.Word})
+	}
+}
+
+func (s *Simulator) waitFor(cond func() bool) {
+	s.t.Helper()
+	deadline := time.Now().Add(settleTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			s.t.Fatal("timed out waiting for the game")
+		}
+		time.Sleep(50 * time.Microsecond)
+	}
+}
+
+// stubClient stands in for a connection. It is not zero-sized, so every
+// client is a distinct pointer.
+type stubClient struct {
+	closed      bool
+	closeCode   int
+	closeReason string
+}
+
+func (c *stubClient) SendMessage([]byte) error { return nil }
+func (c *stubClient) Close() error             { c.closed = true; return nil }
+func (c *stubClient) CloseWithReason(code int, reason string) error {
+	c.closeCode, c.closeReason = code, reason
+	return c.Close()
+}
+func (c *stubClient) Write() {}
+func (c *stubClient) Read()  {}
@@ -3,114 +3,97 @@ package game
 import (
 	"fmt"
 	"log"
-	"math"
 
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
 	"github.com/Ajstraight619/pictionary-server/internal/utils"
 )
 
-func (g *Game) handlePlayerGuess(playerID string, guess string) {
-	// Check if the player is the current drawer.
-	if g.PlayerOrder[g.Round.CurrentDrawerIdx] == playerID {
-		log.Println("Player is the current drawer, returning early")
+// handlePlayerGuess checks a guess at the word being drawn. eventType is the
+// event the guess arrived as, which any error refers to.
+func (g *Game) handlePlayerGuess(playerID, eventType, guess string) {
+	if g.Round.CurrentDrawerID == playerID {
+		g.sendError(playerID, eventType, e.CodeNotYourTurn, "The drawer cannot guess")
 		return
 	}
 
-	if g.timers["turnTimer"] == nil {
-		log.Println("No turn timer found, returning early")
-		return
-	}
-
-	if g.CurrentTurn.WordToGuess == nil {
-		log.Println("No word to guess, returning early")
+	// The turn timer is cancelled as soon as the turn ends, before the phase
+	// moves on.
+	if g.timers["turnTimer"] == nil || g.CurrentTurn.WordToGuess == nil {
+		g.sendError(playerID, eventType, e.CodeWrongPhase, "There is no word to guess right now")
 		return
 	}
 
+	// Players who know the word may only talk among themselves, so they can
+	// not give it away.
 	if g.CurrentTurn.PlayersGuessedCorrectly[playerID] {
-		log.Println("Player already guessed correctly, returning early")
+		g.sendToWordHolders(playerID, guess)
 		return
 	}
 
-	if guess == g.CurrentTurn.WordToGuess.Word {
+	result, distance := defaultGuessMatcher.Match(guess, *g.CurrentTurn.WordToGuess)
+	if result == GuessCorrect {
 		g.CurrentTurn.PlayersGuessedCorrectly[playerID] = true
-		g.Players[playerID].Score += CalculateScore(g)
+		score := CalculateScore(g)
+		g.Players[playerID].Score += score
+		g.CurrentTurn.points[playerID] = score
+		g.CurrentTurn.guessTimes[playerID] = g.clock.Now().Sub(g.CurrentTurn.startedAt)
 		SendGuessMessage(g, playerID, fmt.Sprintf("%s guessed correctly!", g.Players[playerID].Username)) // Send correct message to not give away the answer
-		if g.CurrentTurn.allGuessedCorrectly() {
+		if g.CurrentTurn.allGuessedCorrectly(g.Players) {
 			log.Println("All players have guessed correctly!")
-			g.FlowSignal <- TurnEnded
+			// Cancelling the turn timer ends the turn exactly once.
+			g.cancelTimer("turnTimer")
 		}
-		g.BroadcastGameState()
+		g.broadcastGameState()
 		return
 	}
-	distance := levenshteinDistance(guess, g.CurrentTurn.WordToGuess.Word)
-	if distance <= 2 {
+	if result == GuessClose {
 		log.Printf("Player %s guessed close! (distance: %d)", playerID, distance)
 		SendGuessMessage(g, playerID, fmt.Sprintf("%s guess is close!", g.Players[playerID].Username)) // Send close message to not give away the answer
 
 	} else {
 		log.Printf("Player %s guessed: %s (distance: %d)", playerID, guess, distance)
-		SendGuessMessage(g, playerID, guess) // Simply return the guess
+		SendGuessMessage(g, playerID, g.blocklist.Filter(guess)) // Simply return the guess
 	}
 }
 
+// CalculateScore scores a correct guess made now with the game's scoring
+// strategy, scaled by the word's difficulty. The guesser must already be
+// marked as having guessed correctly.
 func CalculateScore(g *Game) int {
-
-	turnTimeRemaining := g.GetRemainingTime("turnTimer")
-	// Ensure that we have a valid timer duration.
-	duration := g.timers["turnTimer"].duration
-	if duration == 0 {
-		return 0
+	points := g.scorer().GuessPoints(g.guessContext())
+	if word := g.CurrentTurn.WordToGuess; word != nil {
+		points = int(float64(points) * DifficultyMultiplier(word.Difficulty))
 	}
-	// Score is proportional to the remaining time.
-	score := int(100 * (float64(turnTimeRemaining) / float64(duration)))
-	return score
-
-}
-
-func levenshteinDistance(s1, s2 string) int {
-	m, n := len(s1), len(s2)
-	dp := make([][]int, m+1)
-
-	// Initialize the DP table
-	for i := range dp {
-		dp[i] = make([]int, n+1)
-	}
-
-	// Base cases
-	for i := 0; i <= m; i++ {
-		dp[i][0] = i
-	}
-	for j := 0; j <= n; j++ {
-		dp[0][j] = j
-	}
-
-	for i := 1; i <= m; i++ {
-		for j := 1; j <= n; j++ {
-			if s1[i-1] == s2[j-1] {
-				dp[i][j] = dp[i-1][j-1]
-			} else {
-				dp[i][j] = 1 + int(math.Min(math.Min(
-					float64(dp[i-1][j]), // Deletion
-					float64(dp[i][j-1]), // Insertion
-				), float64(dp[i-1][j-1]))) // Substitution
-			}
-		}
-	}
-
-	return dp[m][n]
+	return points
 }
 
 func SendGuessMessage(g *Game, playerID, result string) {
 
 	playerColor := g.getPlayerColor(playerID)
 	log.Printf("Sending guess message for player %s with color %s", playerID, playerColor)
-	payload := map[string]interface{}{
-		"guess":    result,
-		"username": g.Players[playerID].Username,
-		"color":    playerColor,
-	}
-	if b, err := utils.CreateMessage("playerGuess", payload); err == nil {
-		g.Messenger.BroadcastMessage(b)
-	} else {
+	g.broadcast(e.PlayerGuess, e.PlayerGuessMessage{
+		Guess:    result,
+		Username: g.Players[playerID].Username,
+		Color:    playerColor,
+	})
+}
+
+// sendToWordHolders relays a message from a player who has already guessed
+// to the drawer and the other players who have guessed.
+func (g *Game) sendToWordHolders(playerID, text string) {
+	b, err := utils.CreateMessage(e.PlayerGuess, e.PlayerGuessMessage{
+		Guess:       g.blocklist.Filter(text),
+		Username:    g.Players[playerID].Username,
+		Color:       g.getPlayerColor(playerID),
+		GuessedOnly: true,
+	})
+	if err != nil {
 		log.Println("error marshalling guessFeedback message:", err)
+		return
+	}
+	for _, id := range g.PlayerOrder {
+		if id == g.CurrentTurn.CurrentDrawerID || g.CurrentTurn.PlayersGuessedCorrectly[id] {
+			g.Messenger.SendToPlayer(id, b)
+		}
 	}
 }
@@ -0,0 +1,109 @@
+package game_test
+
+import (
+	"encoding/json"
+	"testing"
+	"unicode"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHintPacing(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		words         []string
+		turnTimeLimit int
+		// want maps the elapsed seconds at which hints were broadcast to the
+		// number of letters revealed by then.
+		want map[int]int
+	}{
+		{
+			// Four of eight letters, one every 20/5 seconds.
+			name:          "half the letters",
+			words:         []string{"sandwich", "elephant", "umbrella"},
+			turnTimeLimit: 20,
+			want:          map[int]int{4: 1, 8: 2, 12: 3, 16: 4},
+		},
+		{
+			name:          "spaces are not letters",
+			words:         []string{"ice cream", "red panda", "sea horse"},
+			turnTimeLimit: 10,
+			want:          map[int]int{2: 1, 4: 2, 6: 3, 8: 4},
+		},
+		{
+			// Two of five letters, one every 10/3 seconds, so each lands on
+			// the first whole second after.
+			name:          "uneven interval",
+			words:         []string{"apple", "grape", "lemon"},
+			turnTimeLimit: 10,
+			want:          map[int]int{4: 1, 7: 2},
+		},
+		{
+			name:          "one reveal for short words",
+			words:         []string{"cat", "dog", "owl"},
+			turnTimeLimit: 10,
+			want:          map[int]int{5: 1},
+		},
+		{
+			name:          "nothing for one letter",
+			words:         []string{"a", "b", "c"},
+			turnTimeLimit: 10,
+			want:          map[int]int{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			options := gameOptions
+			options.TurnTimeLimit = tt.turnTimeLimit
+			options.WordSource = shared.WordSourceCustom
+			options.CustomWords = tt.words
+			sim := gametest.New(t, options)
+			sim.JoinN(2)
+			sim.StartGame()
+			sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+			word := []rune(sim.Turn().Word.Word)
+			sim.Messenger.Reset()
+
+			sim.RunUntil(func() bool { return sim.Phase() != g.PhaseDrawing })
+
+			got := map[int]int{}
+			elapsed := 0
+			for _, msg := range sim.Messenger.Messages() {
+				switch msg.Type {
+				case e.TurnTimer:
+					var timer e.TimerMessage
+					require.NoError(t, json.Unmarshal(msg.Payload, &timer))
+					elapsed = tt.turnTimeLimit - timer.TimeRemaining
+				case e.RevealedLetter:
+					var hint e.RevealedLetterMessage
+					require.NoError(t, json.Unmarshal(msg.Payload, &hint))
+					got[elapsed] = checkHint(t, word, hint.RevealedLetters)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// checkHint checks that hint shows word with some of its letters hidden and
+// returns how many letters it reveals.
+func checkHint(t *testing.T, word, hint []rune) int {
+	t.Helper()
+	require.Len(t, hint, len(word))
+	revealed := 0
+	for i, r := range hint {
+		switch {
+		case !unicode.IsLetter(word[i]):
+			assert.Equal(t, word[i], r, "non-letters are always shown")
+		case r == '_':
+		default:
+			assert.Equal(t, word[i], r)
+			revealed++
+		}
+	}
+	return revealed
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"log"
+)
+
+// isHost reports whether playerID holds host privileges.
+func (g *Game) isHost(playerID string) bool {
+	player, exists := g.Players[playerID]
+	return exists && player.IsHost
+}
+
+// migrateHost hands the host role from the given player to whoever has been
+// connected the longest. If nobody else is connected, the next player in join
+// order takes over so the game is never left without a host.
+func (g *Game) migrateHost(fromID string) {
+	var next string
+	for _, id := range g.PlayerOrder {
+		candidate := g.Players[id]
+		if id == fromID || candidate == nil {
+			continue
+		}
+		if next == "" {
+			next = id
+			continue
+		}
+		current := g.Players[next]
+		if candidate.Connected && (!current.Connected || candidate.ConnectedAt.Before(current.ConnectedAt)) {
+			next = id
+		}
+	}
+	if next == "" {
+		return
+	}
+	g.setHost(next)
+	log.Printf("migrateHost: host moved from %s to %s", fromID, next)
+}
+
+// setHost makes playerID the only host.
+func (g *Game) setHost(playerID string) {
+	for id, player := range g.Players {
+		player.IsHost = id == playerID
+	}
+}
+
+// transferHost hands host privileges from the current host to another
+// connected player.
+func (g *Game) transferHost(fromID, toID string) bool {
+	target, exists := g.Players[toID]
+	if !g.isHost(fromID) || !exists || !target.Connected || fromID == toID {
+		return false
+	}
+	g.setHost(toID)
+
+	log.Printf("transferHost: host moved from %s to %s", fromID, toID)
+	g.broadcastGameState()
+	return true
+}
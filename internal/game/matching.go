@@ -0,0 +1,115 @@
+package game
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// GuessResult is how a guess compares to the word being drawn.
+type GuessResult int
+
+const (
+	GuessWrong GuessResult = iota
+	GuessClose
+	GuessCorrect
+)
+
+// maxCloseDistance caps how many edits away a guess may be and still count
+// as close, however long the word is.
+const maxCloseDistance = 3
+
+// GuessMatcher compares guesses with a word and its accepted aliases. Both
+// sides are normalized first: case is folded, accents are stripped and
+// whitespace is trimmed and collapsed.
+type GuessMatcher struct {
+	// KeepPunctuation makes punctuation significant, so "t-shirt" no longer
+	// matches "tshirt".
+	KeepPunctuation bool
+}
+
+// defaultGuessMatcher is the matcher used by games.
+var defaultGuessMatcher = GuessMatcher{}
+
+// Normalize returns s in the form guesses are compared in.
+func (m GuessMatcher) Normalize(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	s, _, err := transform.String(t, s)
+	if err != nil {
+		return ""
+	}
+	s = cases.Fold().String(s)
+	if !m.KeepPunctuation {
+		s = strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Match reports whether guess names word or one of its aliases, and if not,
+// whether it is close to any of them. distance is the smallest edit distance
+// found, counted in characters.
+func (m GuessMatcher) Match(guess string, word shared.Word) (result GuessResult, distance int) {
+	guess = m.Normalize(guess)
+	distance = -1
+	for _, answer := range append([]string{word.Word}, word.Aliases...) {
+		answer = m.Normalize(answer)
+		if answer == "" {
+			continue
+		}
+		d := levenshteinDistance(guess, answer)
+		if d == 0 {
+			return GuessCorrect, 0
+		}
+		if guess != "" && d <= closeDistance(answer) {
+			result = GuessClose
+		}
+		if distance < 0 || d < distance {
+			distance = d
+		}
+	}
+	return result, max(distance, 0)
+}
+
+// closeDistance is how many edits away from answer a guess may be to count as
+// close. Longer words tolerate more typos.
+func closeDistance(answer string) int {
+	return min(1+len([]rune(answer))/6, maxCloseDistance)
+}
+
+// levenshteinDistance counts the single-character edits between s1 and s2.
+func levenshteinDistance(s1, s2 string) int {
+	a, b := []rune(s1), []rune(s2)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1]
+			} else {
+				curr[j] = 1 + min(
+					prev[j],   // Deletion
+					curr[j-1], // Insertion
+					prev[j-1], // Substitution
+				)
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
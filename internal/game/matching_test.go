@@ -0,0 +1,99 @@
+package game_test
+
+import (
+	"strings"
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeGuess(t *testing.T) {
+	var matcher g.GuessMatcher
+	tests := map[string]string{
+		"Ants":               "ants",
+		"  Grass   hopper  ": "grass hopper",
+		"Crème Brûlée":       "creme brulee",
+		"STRASSE":            "strasse",
+		"T-Shirt!":           "tshirt",
+		"\tice\ncream ":      "ice cream",
+		"":                   "",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, matcher.Normalize(in), "normalize %q", in)
+	}
+
+	keep := g.GuessMatcher{KeepPunctuation: true}
+	assert.Equal(t, "t-shirt!", keep.Normalize("T-Shirt!"))
+}
+
+func TestMatchGuess(t *testing.T) {
+	var matcher g.GuessMatcher
+	word := shared.Word{Word: "Butterfly", Aliases: []string{"Butterflies"}}
+
+	tests := []struct {
+		guess    string
+		result   g.GuessResult
+		distance int
+	}{
+		{"butterfly", g.GuessCorrect, 0},
+		{" BUTTERFLY ", g.GuessCorrect, 0},
+		{"butterflies", g.GuessCorrect, 0},
+		{"buterfly", g.GuessClose, 1},
+		{"butterflys", g.GuessClose, 1},
+		{"butter", g.GuessWrong, 3},
+		{"moth", g.GuessWrong, 8},
+		{"", g.GuessWrong, 9},
+	}
+	for _, tt := range tests {
+		result, distance := matcher.Match(tt.guess, word)
+		assert.Equal(t, tt.result, result, "match %q", tt.guess)
+		assert.Equal(t, tt.distance, distance, "distance for %q", tt.guess)
+	}
+}
+
+func TestMatchGuessAccentsAndRunes(t *testing.T) {
+	var matcher g.GuessMatcher
+
+	result, _ := matcher.Match("cafe", shared.Word{Word: "Café"})
+	assert.Equal(t, g.GuessCorrect, result)
+
+	// Distances count characters, not bytes.
+	result, distance := matcher.Match("日本", shared.Word{Word: "日本語"})
+	assert.Equal(t, g.GuessClose, result)
+	assert.Equal(t, 1, distance)
+}
+
+func TestCloseGuessScalesWithWordLength(t *testing.T) {
+	var matcher g.GuessMatcher
+
+	// One typo is close for a short word, two are not.
+	result, _ := matcher.Match("ant", shared.Word{Word: "Ants"})
+	assert.Equal(t, g.GuessClose, result)
+	result, _ = matcher.Match("an", shared.Word{Word: "Ants"})
+	assert.Equal(t, g.GuessWrong, result)
+
+	// Longer words tolerate more.
+	result, _ = matcher.Match("grashoper", shared.Word{Word: "Grasshopper"})
+	assert.Equal(t, g.GuessClose, result)
+	result, _ = matcher.Match("grashop", shared.Word{Word: "Grasshopper"})
+	assert.Equal(t, g.GuessWrong, result)
+}
+
+func TestGuessIsNormalizedInGame(t *testing.T) {
+	sim := newTestGame(t, 2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+
+	turn := sim.Turn()
+	guesser := sim.Players[0]
+	if guesser == turn.DrawerID {
+		guesser = sim.Players[1]
+	}
+	sim.Send(guesser, e.PlayerGuess, e.PlayerGuessPayload{Guess: "  " + strings.ToUpper(turn.Word.Word) + " "})
+	sim.Settle()
+
+	assert.Equal(t, g.PhaseTurnResults, sim.Phase())
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"log"
+	"unicode/utf8"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+)
+
+// maxCloseReason is the most a WebSocket close frame can carry as its reason:
+// 125 bytes of control frame payload less the two byte close code.
+const maxCloseReason = 123
+
+// BanList remembers who was banned from a game, both by session identity and
+// by address so a banned player cannot simply rejoin under a new ID.
+type BanList struct {
+	playerIDs map[string]bool
+	ips       map[string]bool
+}
+
+func NewBanList() *BanList {
+	return &BanList{
+		playerIDs: make(map[string]bool),
+		ips:       make(map[string]bool),
+	}
+}
+
+func (b *BanList) add(playerID, ip string) {
+	b.playerIDs[playerID] = true
+	if ip != "" {
+		b.ips[ip] = true
+	}
+}
+
+func (b *BanList) contains(playerID, ip string) bool {
+	return (playerID != "" && b.playerIDs[playerID]) || (ip != "" && b.ips[ip])
+}
+
+// IsBanned reports whether the session or address has been banned from the
+// game. Either argument may be empty.
+func (g *Game) IsBanned(playerID, ip string) bool {
+	banned := false
+	g.query(func() { banned = g.bans.contains(playerID, ip) })
+	return banned
+}
+
+// kickPlayer removes a player on behalf of the host and closes their
+// connection. With ban set, the player's session and address are also barred
+// from rejoining.
+func (g *Game) kickPlayer(hostID, playerID, reason string, ban bool) bool {
+	player, exists := g.Players[playerID]
+	if !g.isHost(hostID) || !exists || hostID == playerID {
+		return false
+	}
+	if ban {
+		g.bans.add(playerID, player.IP)
+	}
+	reason = kickReason(g.blocklist.Filter(reason))
+	if timer, ok := g.disconnectTimers[playerID]; ok {
+		timer.Stop()
+		delete(g.disconnectTimers, playerID)
+	}
+	client := player.Client
+	player.Client = nil
+	player.Connected = false
+
+	g.removePlayer(playerID)
+	log.Printf("kickPlayer: %s removed %s (ban: %t, reason: %q)", hostID, playerID, ban, reason)
+
+	if client != nil {
+		code := e.CloseKicked
+		if ban {
+			code = e.CloseBanned
+		}
+		// The client's writer sends the close frame, so a slow peer cannot
+		// hold up the game loop.
+		client.CloseWithReason(code, reason)
+	}
+
+	g.broadcast(e.PlayerKicked, e.PlayerKickedMessage{
+		PlayerID: playerID,
+		Banned:   ban,
+		Reason:   reason,
+	})
+
+	g.resolveTurnAfterDeparture(playerID)
+	g.broadcastGameState()
+	return true
+}
+
+// kickReason cuts reason down to fit in a close frame without splitting a
+// rune.
+func kickReason(reason string) string {
+	if len(reason) <= maxCloseReason {
+		return reason
+	}
+	cut := maxCloseReason
+	for cut > 0 && !utf8.RuneStart(reason[cut]) {
+		cut--
+	}
+	return reason[:cut]
+}
@@ -0,0 +1,76 @@
+package game_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBans(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    string
+		banned   bool
+		wantCode int
+	}{
+		{name: "kick", event: e.KickPlayer, wantCode: e.CloseKicked},
+		{name: "ban", event: e.BanPlayer, banned: true, wantCode: e.CloseBanned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := newTestGame(t, 3)
+			victim, bystander := sim.Players[1], sim.Players[2]
+			sim.ReconnectFrom(victim, "10.0.0.1")
+			sim.ReconnectFrom(bystander, "10.0.0.2")
+
+			sim.Send(sim.Host(), tt.event, e.KickPlayerPayload{PlayerID: victim, Reason: "bye"})
+
+			require.Nil(t, sim.Game.GetPlayerByID(victim))
+			code, reason := sim.CloseFrame(victim)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, "bye", reason)
+			kicked := lastMessage[e.PlayerKickedMessage](t, sim, e.PlayerKicked)
+			assert.Equal(t, e.PlayerKickedMessage{PlayerID: victim, Banned: tt.banned, Reason: "bye"}, kicked)
+
+			assert.Equal(t, tt.banned, sim.Game.IsBanned(victim, ""), "by player ID")
+			assert.Equal(t, tt.banned, sim.Game.IsBanned("", "10.0.0.1"), "by IP")
+			assert.Equal(t, tt.banned, sim.Game.IsBanned("newcomer", "10.0.0.1"), "new ID from a banned IP")
+			assert.False(t, sim.Game.IsBanned(bystander, "10.0.0.2"))
+			assert.False(t, sim.Game.IsBanned("", ""))
+		})
+	}
+}
+
+func TestOnlyTheHostCanBan(t *testing.T) {
+	sim := newTestGame(t, 3)
+	victim := sim.Players[2]
+
+	sim.Send(sim.Players[1], e.BanPlayer, e.KickPlayerPayload{PlayerID: victim})
+	sim.Send(sim.Host(), e.BanPlayer, e.KickPlayerPayload{PlayerID: sim.Host()})
+
+	assert.NotNil(t, sim.Game.GetPlayerByID(victim))
+	assert.NotNil(t, sim.Game.GetPlayerByID(sim.Host()))
+	assert.False(t, sim.Game.IsBanned(victim, ""))
+	assert.False(t, sim.Game.IsBanned(sim.Host(), ""))
+	assert.Equal(t, e.CodeNotHost, lastError(t, sim, sim.Players[1]).Code)
+	assert.Equal(t, e.CodeInvalidTarget, lastError(t, sim, sim.Host()).Code)
+}
+
+func TestKickReasonIsFilteredAndBounded(t *testing.T) {
+	sim := newTestGame(t, 2)
+	sim.Game.SetBlocklist(chat.NewBlocklist("darn"))
+	victim := sim.Players[1]
+
+	// 5 + 2*100 bytes, over the 123 a close frame can carry.
+	sim.Send(sim.Host(), e.KickPlayer, e.KickPlayerPayload{PlayerID: victim, Reason: "darn " + strings.Repeat("é", 100)})
+
+	_, reason := sim.CloseFrame(victim)
+	assert.True(t, utf8.ValidString(reason))
+	assert.Equal(t, "**** "+strings.Repeat("é", 59), reason)
+	assert.Equal(t, reason, lastMessage[e.PlayerKickedMessage](t, sim, e.PlayerKicked).Reason)
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	"github.com/Ajstraight619/pictionary-server/internal/db"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+)
+
+var ErrGameStarted = errors.New("game has already started")
+
+// ValidateOptions checks options that have had their defaults applied: the
+// bounds of each option, that the chosen categories exist and that no custom
+// word is on the blocklist.
+func ValidateOptions(options shared.GameOptions, blocklist *chat.Blocklist) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if err := db.ValidateCategories(options); err != nil {
+		return err
+	}
+	for _, word := range options.CustomWords {
+		if blocklist.Contains(word) {
+			return &shared.OptionsError{Fields: map[string]string{
+				"customWords": fmt.Sprintf("%q is not allowed", word),
+			}}
+		}
+	}
+	return nil
+}
+
+// updateOptions replaces the game options while the game is still in the
+// lobby. The options must already have their defaults applied and have passed
+// ValidateOptions.
+func (g *Game) updateOptions(options shared.GameOptions) error {
+	if g.Status != NotStarted {
+		return ErrGameStarted
+	}
+	if len(g.Players) > options.MaxPlayers {
+		return &shared.OptionsError{Fields: map[string]string{
+			"maxPlayers": fmt.Sprintf("must be at least the current player count (%d)", len(g.Players)),
+		}}
+	}
+	g.Options = options
+
+	log.Printf("updateOptions: options updated to %+v", options)
+	g.broadcastGameState()
+	return nil
+}
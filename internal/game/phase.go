@@ -0,0 +1,60 @@
+package game
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+)
+
+// Phase is the stage the game is in. The game moves through the phases in a
+// fixed order, and every change goes through setPhase, which only allows the
+// transitions listed in phaseTransitions.
+type Phase = e.GamePhase
+
+const (
+	PhaseLobby         Phase = "lobby"
+	PhaseCountdown     Phase = "countdown"
+	PhaseWordSelection Phase = "wordSelection"
+	PhaseDrawing       Phase = "drawing"
+	PhaseTurnResults   Phase = "turnResults"
+	PhaseRoundResults  Phase = "roundResults"
+	PhaseGameOver      Phase = "gameOver"
+)
+
+// phaseTransitions lists the phases each phase may move to. Any phase may
+// also move to PhaseGameOver, since a game can be shut down at any time.
+var phaseTransitions = map[Phase][]Phase{
+	PhaseLobby:         {PhaseCountdown},
+	PhaseCountdown:     {PhaseLobby, PhaseWordSelection},
+	PhaseWordSelection: {PhaseDrawing, PhaseTurnResults},
+	PhaseDrawing:       {PhaseTurnResults},
+	PhaseTurnResults:   {PhaseWordSelection, PhaseRoundResults},
+	PhaseRoundResults:  {PhaseWordSelection, PhaseGameOver},
+}
+
+func canTransition(from, to Phase) bool {
+	return to == PhaseGameOver || slices.Contains(phaseTransitions[from], to)
+}
+
+// setPhase moves the game to the given phase, which is expected to last for
+// duration. A zero duration means the phase has no deadline. It reports false
+// and leaves the game untouched if the transition is not allowed.
+func (g *Game) setPhase(to Phase, duration time.Duration) bool {
+	if !canTransition(g.Phase, to) {
+		log.Printf("Rejected phase transition from %s to %s", g.Phase, to)
+		return false
+	}
+	log.Printf("Phase changed from %s to %s", g.Phase, to)
+	g.Phase = to
+	g.phaseDeadline = time.Time{}
+	if duration > 0 {
+		g.phaseDeadline = g.clock.Now().Add(duration)
+	}
+	return true
+}
+
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
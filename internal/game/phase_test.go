@@ -0,0 +1,128 @@
+package game_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lastError returns the last error sent to playerID.
+func lastError(t *testing.T, sim *gametest.Simulator, playerID string) e.ErrorPayload {
+	t.Helper()
+	msg, ok := sim.Messenger.Last(playerID, e.Error)
+	require.True(t, ok, "no error sent to %s", playerID)
+	var payload e.ErrorPayload
+	require.NoError(t, json.Unmarshal(msg.Payload, &payload))
+	return payload
+}
+
+func TestPhaseProgression(t *testing.T) {
+	sim := newTestGame(t, 2)
+	assert.Equal(t, g.PhaseLobby, sim.Phase())
+
+	sim.StartGame()
+	assert.Equal(t, g.PhaseCountdown, sim.Phase())
+	assert.NotNil(t, sim.Game.GetGameState().PhaseDeadline)
+
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseWordSelection })
+	turn := sim.Turn()
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+	sim.Send(turn.DrawerID, e.SelectWord, e.SelectWordPayload{WordID: sim.Turn().SelectableWords[0].Id})
+	assert.Equal(t, g.PhaseDrawing, sim.Phase())
+
+	sim.GuessAll()
+	assert.Equal(t, g.PhaseTurnResults, sim.Phase())
+
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseWordSelection })
+	assert.NotEqual(t, turn.DrawerID, sim.Turn().DrawerID)
+
+	sim.PlayToEnd(nil)
+	state := sim.Game.GetGameState()
+	assert.Equal(t, g.PhaseGameOver, state.Phase)
+	assert.Nil(t, state.PhaseDeadline)
+}
+
+func TestCountdownCanBeStopped(t *testing.T) {
+	sim := newTestGame(t, 2)
+
+	sim.StartGame()
+	sim.Step()
+	sim.Send(sim.Host(), e.StopTimer, e.StopTimerPayload{TimerType: "startGameCountdown"})
+
+	state := sim.Game.GetGameState()
+	assert.Equal(t, g.PhaseLobby, state.Phase)
+	assert.Equal(t, g.NotStarted, state.Status)
+}
+
+func TestOutOfPhaseEventsAreRejected(t *testing.T) {
+	sim := newTestGame(t, 2)
+	guesser := sim.Players[1]
+
+	sim.Send(guesser, e.PlayerGuess, e.PlayerGuessPayload{Guess: "ants"})
+	assert.Equal(t, e.ErrorPayload{
+		Event:   e.PlayerGuess,
+		Code:    e.CodeWrongPhase,
+		Message: "playerGuess is not allowed during lobby",
+	}, lastError(t, sim, guesser))
+
+	sim.StartGame()
+	sim.Send(sim.Host(), e.StartTimer, e.StartTimerPayload{TimerType: "startGameCountdown"})
+	assert.Equal(t, e.CodeWrongPhase, lastError(t, sim, sim.Host()).Code)
+	assert.Equal(t, g.PhaseCountdown, sim.Phase())
+
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseWordSelection })
+	sim.Send(guesser, e.StrokeEnd, struct{}{})
+	assert.Equal(t, e.StrokeEnd, lastError(t, sim, guesser).Event)
+}
+
+func TestSelectWordAfterAutoSelection(t *testing.T) {
+	sim := newTestGame(t, 2)
+
+	sim.StartGame()
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+	offered := sim.Turn().SelectableWords
+	sim.RunUntil(func() bool { return sim.Turn().Word != nil })
+	turn := sim.Turn()
+	require.Equal(t, g.PhaseWordSelection, turn.Phase)
+
+	// Picking the word the timer chose is a no-op rather than an error.
+	sim.Messenger.Reset()
+	sim.Send(turn.DrawerID, e.SelectWord, e.SelectWordPayload{WordID: turn.Word.Id})
+	_, failed := sim.Messenger.Last(turn.DrawerID, e.Error)
+	assert.False(t, failed)
+
+	for _, word := range offered {
+		if word.Id != turn.Word.Id {
+			sim.Send(turn.DrawerID, e.SelectWord, e.SelectWordPayload{WordID: word.Id})
+			assert.Equal(t, e.CodeAlreadySelected, lastError(t, sim, turn.DrawerID).Code)
+			break
+		}
+	}
+
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+	assert.Equal(t, turn.Word.Word, sim.Turn().Word.Word)
+}
+
+func TestDrawerLeavesAfterAutoSelection(t *testing.T) {
+	sim := newTestGame(t, 3)
+
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Turn().Word != nil })
+	turn := sim.Turn()
+	require.Equal(t, g.PhaseWordSelection, turn.Phase)
+
+	// The drawer leaves during the pause between the automatic selection and
+	// the drawing phase.
+	sim.Disconnect(turn.DrawerID)
+	require.Equal(t, g.PhaseTurnResults, sim.Phase())
+
+	sim.Step()
+	sim.Step()
+	assert.Equal(t, g.PhaseTurnResults, sim.Phase(), "the results must not be cut short")
+	assert.Equal(t, turn.DrawerID, sim.Turn().DrawerID)
+}
@@ -1,7 +1,9 @@
 package game
 
 import (
+	"errors"
 	"log"
+	"slices"
 
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
 )
@@ -31,51 +33,98 @@ func (g *Game) NewPlayer(id, username string, isHost bool) *shared.Player {
 	}
 }
 
-func (g *Game) AddPlayer(player *shared.Player) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+var (
+	ErrGameFull   = errors.New("game is full")
+	ErrGameClosed = errors.New("game is closed")
+)
+
+// AddPlayer seats a new player, or returns ErrGameFull once MaxPlayers is
+// reached. The game takes ownership of player; callers must not modify it
+// afterwards.
+func (g *Game) AddPlayer(player *shared.Player) error {
+	var err error
+	if !g.do(func() { err = g.addPlayer(player) }) {
+		return ErrGameClosed
+	}
+	return err
+}
+
+func (g *Game) addPlayer(player *shared.Player) error {
 	if _, exists := g.Players[player.ID]; exists {
-		return
+		return nil
 	}
-	// Assign a unique color from the available pool.
-	if len(g.AvailableColors) > 0 {
-		player.Color = g.AvailableColors[0]
-		g.AvailableColors = g.AvailableColors[1:]
-	} else {
-		// No unique colors left, fallback to a default value or error.
-		player.Color = "#FFFFFF" // or handle this case as needed.
+	if err := g.checkCapacity(); err != nil {
+		return err
 	}
+	player.Color = g.takeColor("")
 	g.Players[player.ID] = player
 	g.PlayerOrder = append(g.PlayerOrder, player.ID)
 	log.Printf("AddPlayer: added player %s with color %s; current PlayerOrder: %+v", player.ID, player.Color, g.PlayerOrder)
+	return nil
+}
+
+// checkCapacity returns ErrGameFull if there is no room for another player.
+func (g *Game) checkCapacity() error {
+	if g.Options.MaxPlayers > 0 && len(g.Players) >= g.Options.MaxPlayers {
+		return ErrGameFull
+	}
+	return nil
+}
 
+// takeColor assigns a unique color from the available pool, preferring the
+// given color if it is still free.
+func (g *Game) takeColor(preferred string) string {
+	if i := slices.Index(g.AvailableColors, preferred); i >= 0 {
+		g.AvailableColors = slices.Delete(g.AvailableColors, i, i+1)
+		return preferred
+	}
+	if len(g.AvailableColors) > 0 {
+		color := g.AvailableColors[0]
+		g.AvailableColors = g.AvailableColors[1:]
+		return color
+	}
+	// No unique colors left, fallback to a default value.
+	return "#FFFFFF"
 }
 
 func (g *Game) RemovePlayer(playerID string) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+	g.do(func() { g.removePlayer(playerID) })
+}
+
+func (g *Game) removePlayer(playerID string) {
 	if player, ok := g.Players[playerID]; ok {
+		if player.IsHost {
+			g.migrateHost(playerID)
+		}
 		// Return the player's color back to the pool.
 		g.AvailableColors = append(g.AvailableColors, player.Color)
 		delete(g.Players, playerID)
+		g.chatLimiter.Forget(playerID)
 	}
 
-	for i, id := range g.PlayerOrder {
-		if id == playerID {
-			g.PlayerOrder = append(g.PlayerOrder[:i], g.PlayerOrder[i+1:]...)
-			break
-		}
+	if i := slices.Index(g.PlayerOrder, playerID); i >= 0 {
+		g.PlayerOrder = slices.Delete(g.PlayerOrder, i, i+1)
+		g.Round.removePlayer(i, len(g.PlayerOrder))
 	}
 }
 
+// GetPlayerByID returns a copy of the player, or nil if they are not in the
+// game.
 func (g *Game) GetPlayerByID(playerID string) *shared.Player {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
-	if player, ok := g.Players[playerID]; ok {
-		return player
-	}
-	log.Printf("GetPlayerByID: player with ID %s not found. Current players: %+v", playerID, g.PlayerOrder)
-	return nil
+	var player *shared.Player
+	g.query(func() {
+		if p, ok := g.Players[playerID]; ok {
+			player = clonePlayer(p)
+			return
+		}
+		log.Printf("GetPlayerByID: player with ID %s not found. Current players: %+v", playerID, g.PlayerOrder)
+	})
+	return player
+}
+
+func clonePlayer(p *shared.Player) *shared.Player {
+	clone := *p
+	return &clone
 }
 
 func (g *Game) getPlayerColor(playerID string) string {
@@ -86,19 +135,18 @@ func (g *Game) getPlayerColor(playerID string) string {
 }
 
 func (g *Game) CheckForHost() bool {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-	for _, player := range g.Players {
-		if player.IsHost {
-			return true
+	found := false
+	g.query(func() {
+		for _, player := range g.Players {
+			if player.IsHost {
+				found = true
+			}
 		}
-	}
-	return false
+	})
+	return found
 }
 
-func (g *Game) ClearDrawingPlayers() {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+func (g *Game) clearDrawingPlayers() {
 	for _, player := range g.Players {
 		player.IsDrawing = false
 	}
@@ -0,0 +1,112 @@
+package game_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptedEventsAreAcked(t *testing.T) {
+	sim := newTestGame(t, 2)
+	player := sim.Players[1]
+
+	require.NoError(t, sim.Messenger.InjectJSON(player, []byte(`{"type":"chatMessage","requestID":"r1","payload":{"text":"hi"}}`)))
+	sim.Settle()
+
+	acks := messagesOf(sim, e.Ack)
+	require.Len(t, acks, 1)
+	assert.Equal(t, player, acks[0].To)
+	var ack e.AckPayload
+	require.NoError(t, json.Unmarshal(acks[0].Payload, &ack))
+	assert.Equal(t, e.AckPayload{Event: e.Chat, RequestID: "r1"}, ack)
+
+	// Events without a request ID are not acked.
+	sim.Send(player, e.Chat, e.ChatPayload{Text: "hello"})
+	sim.Settle()
+	assert.Len(t, messagesOf(sim, e.Ack), 1)
+}
+
+func TestRejectedEventsGetOneError(t *testing.T) {
+	sim := newTestGame(t, 2)
+	host, guest := sim.Host(), sim.Players[1]
+
+	tests := []struct {
+		name     string
+		playerID string
+		message  string
+		code     string
+	}{
+		{"malformed payload", guest, `{"type":"chatMessage","requestID":"r","payload":{"text":7}}`, e.CodeInvalidPayload},
+		{"unknown event", guest, `{"type":"dance","requestID":"r","payload":{}}`, e.CodeUnknownEvent},
+		{"host only", guest, `{"type":"startTimer","requestID":"r","payload":{"timerType":"startGameCountdown"}}`, e.CodeNotHost},
+		{"invalid options", host, `{"type":"updateOptions","requestID":"r","payload":{"options":{"roundLimit":99}}}`, e.CodeInvalidOptions},
+		{"invalid target", host, `{"type":"kickPlayer","requestID":"r","payload":{"playerID":"nobody"}}`, e.CodeInvalidTarget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim.Messenger.Reset()
+			require.NoError(t, sim.Messenger.InjectJSON(tt.playerID, []byte(tt.message)))
+			sim.Settle()
+
+			errs := messagesOf(sim, e.Error)
+			require.Len(t, errs, 1)
+			assert.Equal(t, tt.playerID, errs[0].To)
+			err := lastError(t, sim, tt.playerID)
+			assert.Equal(t, tt.code, err.Code)
+			assert.Equal(t, "r", err.RequestID)
+			assert.Empty(t, messagesOf(sim, e.Ack))
+		})
+	}
+}
+
+func TestOnlyTheDrawerCanDraw(t *testing.T) {
+	sim := newTestGame(t, 2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+
+	guesser := guessersOf(sim)[0]
+	sim.Send(guesser, e.StrokeBegin, e.StrokeBeginPayload{Color: "#000000", Width: 4})
+	sim.Settle()
+	assert.Equal(t, e.CodeNotYourTurn, lastError(t, sim, guesser).Code)
+
+	drawer := sim.Turn().DrawerID
+	sim.Send(drawer, e.StrokeBegin, e.StrokeBeginPayload{Color: "black", Width: 4})
+	sim.Settle()
+	assert.Equal(t, e.CodeInvalidPayload, lastError(t, sim, drawer).Code)
+}
+
+func TestDrawerGuessIsNotAcked(t *testing.T) {
+	sim := newTestGame(t, 2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+	sim.Messenger.Reset()
+
+	drawer := sim.Turn().DrawerID
+	msg := `{"type":"playerGuess","requestID":"g1","payload":{"guess":"ants"}}`
+	require.NoError(t, sim.Messenger.InjectJSON(drawer, []byte(msg)))
+	sim.Settle()
+
+	err := lastError(t, sim, drawer)
+	assert.Equal(t, e.CodeNotYourTurn, err.Code)
+	assert.Equal(t, "g1", err.RequestID)
+	assert.Empty(t, messagesOf(sim, e.Ack))
+}
+
+func TestUpdateOptionsIsAckedOnceApplied(t *testing.T) {
+	sim := newTestGame(t, 2)
+
+	require.NoError(t, sim.Messenger.InjectJSON(sim.Host(), []byte(`{"type":"updateOptions","requestID":"o1","payload":{"options":{"roundLimit":5}}}`)))
+	sim.Settle()
+
+	assert.Equal(t, 5, sim.Game.GetGameState().Options.RoundLimit)
+	assert.Empty(t, messagesOf(sim, e.Error))
+	acks := messagesOf(sim, e.Ack)
+	require.Len(t, acks, 1)
+	var ack e.AckPayload
+	require.NoError(t, json.Unmarshal(acks[0].Payload, &ack))
+	assert.Equal(t, e.AckPayload{Event: e.UpdateOptions, RequestID: "o1"}, ack)
+}
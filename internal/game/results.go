@@ -0,0 +1,115 @@
+package game
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+)
+
+const (
+	// turnResultsDuration is how long the word and the turn's scores are shown
+	// before the next turn starts.
+	turnResultsDuration = 5 * time.Second
+	// roundResultsDuration is how long the standings are shown between rounds.
+	roundResultsDuration = 5 * time.Second
+	podiumPlaces         = 3
+)
+
+// awardDrawerBonus scores the drawer with the game's scoring strategy once
+// the turn is over.
+func (t *Turn) awardDrawerBonus(g *Game) {
+	drawer, ok := g.Players[t.CurrentDrawerID]
+	if !ok {
+		return
+	}
+	summary := TurnSummary{}
+	for _, id := range g.PlayerOrder {
+		if id == t.CurrentDrawerID {
+			continue
+		}
+		summary.Guessers++
+		if t.PlayersGuessedCorrectly[id] {
+			summary.Points = append(summary.Points, t.points[id])
+		}
+	}
+	bonus := g.scorer().DrawerPoints(summary)
+	if bonus <= 0 {
+		return
+	}
+	t.points[drawer.ID] += bonus
+	drawer.Score += bonus
+}
+
+func (g *Game) broadcastTurnResults(t *Turn) {
+	results := make([]e.PlayerTurnResult, 0, len(g.PlayerOrder))
+	for _, id := range g.PlayerOrder {
+		player, ok := g.Players[id]
+		if !ok {
+			continue
+		}
+		result := e.PlayerTurnResult{
+			PlayerID: id,
+			Username: player.Username,
+			Points:   t.points[id],
+			Guessed:  t.PlayersGuessedCorrectly[id],
+			Score:    player.Score,
+		}
+		if d, ok := t.guessTimes[id]; ok {
+			result.TimeToGuessMs = d.Milliseconds()
+		}
+		results = append(results, result)
+	}
+
+	g.broadcast(e.TurnResults, e.TurnResultsMessage{
+		Word:        t.WordToGuess,
+		DrawerID:    t.CurrentDrawerID,
+		DrawerBonus: t.points[t.CurrentDrawerID],
+		Results:     results,
+	})
+}
+
+func (g *Game) broadcastRoundResults() {
+	g.broadcast(e.RoundResults, e.RoundResultsMessage{
+		Round:     g.Round.Count,
+		Standings: g.standings(),
+	})
+}
+
+func (g *Game) broadcastPodium() {
+	var places []e.Standing
+	for _, standing := range g.standings() {
+		if standing.Rank > podiumPlaces {
+			break
+		}
+		places = append(places, standing)
+	}
+	log.Printf("Podium: %+v", places)
+	g.broadcast(e.Podium, e.PodiumMessage{Places: places})
+}
+
+// standings ranks the players by score. Tied players share a rank, and the
+// next rank skips ahead accordingly, as in 1, 1, 3.
+func (g *Game) standings() []e.Standing {
+	ids := slices.Clone(g.PlayerOrder)
+	slices.SortStableFunc(ids, func(a, b string) int {
+		return g.Players[b].Score - g.Players[a].Score
+	})
+
+	standings := make([]e.Standing, 0, len(ids))
+	for i, id := range ids {
+		player := g.Players[id]
+		rank := i + 1
+		if i > 0 && standings[i-1].Score == player.Score {
+			rank = standings[i-1].Rank
+		}
+		standings = append(standings, e.Standing{
+			PlayerID: id,
+			Username: player.Username,
+			Score:    player.Score,
+			Rank:     rank,
+		})
+	}
+	return standings
+}
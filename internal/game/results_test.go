@@ -0,0 +1,110 @@
+package game_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lastMessage[T any](t *testing.T, sim *gametest.Simulator, msgType string) T {
+	t.Helper()
+	msg, ok := sim.Messenger.Last(sim.Host(), msgType)
+	require.True(t, ok, "no %s message sent", msgType)
+	var payload T
+	require.NoError(t, json.Unmarshal(msg.Payload, &payload))
+	return payload
+}
+
+func TestTurnResults(t *testing.T) {
+	sim := gametest.New(t, shared.GameOptions{
+		MaxPlayers:          4,
+		TurnTimeLimit:       10,
+		RoundLimit:          1,
+		WordSelectTimeLimit: 5,
+		StartCountdown:      3,
+		Scoring:             shared.ScoringDrawerShare,
+	})
+	drawer, guesser, misser := sim.Join("drawer"), sim.Join("guesser"), sim.Join("misser")
+
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+	word := sim.Turn().Word
+	sim.Step()
+	sim.Step()
+	sim.Send(guesser, e.PlayerGuess, e.PlayerGuessPayload{Guess: word.Word})
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseTurnResults })
+
+	results := lastMessage[e.TurnResultsMessage](t, sim, e.TurnResults)
+	require.NotNil(t, results.Word)
+	assert.Equal(t, word.Word, results.Word.Word)
+	assert.Equal(t, drawer, results.DrawerID)
+	require.Len(t, results.Results, 3)
+
+	guessed, missed := results.Results[1], results.Results[2]
+	assert.Equal(t, guesser, guessed.PlayerID)
+	assert.True(t, guessed.Guessed)
+	assert.Positive(t, guessed.Points)
+	assert.EqualValues(t, 2000, guessed.TimeToGuessMs)
+	assert.Equal(t, misser, missed.PlayerID)
+	assert.False(t, missed.Guessed)
+	assert.Zero(t, missed.Points)
+
+	// The drawer earns half of the one correct guess.
+	assert.Equal(t, guessed.Points/2, results.DrawerBonus)
+	assert.Equal(t, results.DrawerBonus, results.Results[0].Points)
+	assert.NotNil(t, sim.Game.GetGameState().PhaseDeadline)
+}
+
+func TestRoundResultsAndPodium(t *testing.T) {
+	sim := newTestGame(t, 4)
+
+	sim.StartGame()
+	sim.PlayToEnd(nil)
+
+	// Nobody guessed, so everyone ties for first place.
+	round := lastMessage[e.RoundResultsMessage](t, sim, e.RoundResults)
+	assert.Equal(t, gameOptions.RoundLimit, round.Round)
+	require.Len(t, round.Standings, 4)
+	for _, standing := range round.Standings {
+		assert.Equal(t, 1, standing.Rank)
+	}
+
+	podium := lastMessage[e.PodiumMessage](t, sim, e.Podium)
+	assert.Len(t, podium.Places, 4)
+}
+
+func TestStandingsRankTies(t *testing.T) {
+	sim := gametest.New(t, shared.GameOptions{
+		MaxPlayers:          4,
+		TurnTimeLimit:       10,
+		RoundLimit:          1,
+		WordSelectTimeLimit: 5,
+		StartCountdown:      3,
+	})
+	sim.JoinN(4)
+
+	sim.StartGame()
+	guessed := false
+	sim.PlayToEnd(func() {
+		// Only the first word is guessed, by everyone at once.
+		if !guessed && sim.Turn().Drawing {
+			sim.GuessAll()
+			guessed = true
+		}
+	})
+
+	podium := lastMessage[e.PodiumMessage](t, sim, e.Podium)
+	// Three guessers tie on the same score and share first place. The drawer
+	// earns nothing and misses the podium.
+	require.Len(t, podium.Places, 3)
+	for _, place := range podium.Places {
+		assert.Equal(t, 1, place.Rank)
+		assert.Positive(t, place.Score)
+	}
+}
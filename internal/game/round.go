@@ -1,11 +1,10 @@
 package game
 
 import (
-	"log"
 	"slices"
 
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
-	"github.com/Ajstraight619/pictionary-server/internal/utils"
 )
 
 type Round struct {
@@ -24,6 +23,14 @@ func InitRound() *Round {
 	}
 }
 
+func (r *Round) state() e.RoundState {
+	return e.RoundState{
+		Count:           r.Count,
+		PlayersDrawn:    slices.Clone(r.PlayersDrawn),
+		CurrentDrawerID: r.CurrentDrawerID,
+	}
+}
+
 func (r *Round) Reset() {
 	r.Count++
 	r.CurrentDrawerIdx = 0
@@ -32,51 +39,40 @@ func (r *Round) Reset() {
 }
 
 func (r *Round) Start(g *Game) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-
 	if r.Count == 0 { // Only set the count on the very first round.
 		r.Count = 1
 	}
-	r.setInitialDrawer(g)
-	g.FlowSignal <- TurnStarted
+	r.NextDrawer(g)
+	g.signal(TurnStarted)
 }
 
 func (r *Round) Next(g *Game) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
 	r.Reset()
-	g.FlowSignal <- RoundStarted
+	g.signal(RoundStarted)
 }
 
-func (r *Round) setInitialDrawer(g *Game) {
-	if len(g.PlayerOrder) == 0 {
-		return
-	}
-	firstID := g.PlayerOrder[0]
-	g.Players[firstID].IsDrawing = true
-	r.CurrentDrawerID = firstID
-	r.CurrentDrawerIdx = 0
-	g.CurrentTurn = NewTurn(firstID)
-	msgType := "drawingPlayerChanged"
-	payload := g.Players[firstID]
-	if b, err := utils.CreateMessage(msgType, payload); err == nil {
-		g.Messenger.BroadcastMessage(b)
-	} else {
-		log.Println("error marshalling message:", err)
+// nextDrawer returns the position in the order and the ID of the first
+// connected player who has not drawn this round, or "" if there is none.
+// Players waiting to reconnect are skipped; they may still draw later in the
+// round if they come back in time.
+func (r *Round) nextDrawer(g *Game) (int, string) {
+	for i, id := range g.PlayerOrder {
+		if g.Players[id].Connected && !slices.Contains(r.PlayersDrawn, id) {
+			return i, id
+		}
 	}
+	return 0, ""
 }
 
+// NextDrawer hands the turn to the first connected player in order who has
+// not drawn this round. Working from IDs rather than the stored index keeps
+// rotation correct when players leave mid-round.
 func (r *Round) NextDrawer(g *Game) *shared.Player {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-
-	if len(g.PlayerOrder) == 0 {
+	idx, newID := r.nextDrawer(g)
+	if newID == "" {
 		return nil
 	}
-	// Move to the next drawer.
-	r.CurrentDrawerIdx = (r.CurrentDrawerIdx + 1) % len(g.PlayerOrder)
-	newID := g.PlayerOrder[r.CurrentDrawerIdx]
+	r.CurrentDrawerIdx = idx
 
 	// Create a new turn for the new drawer.
 	g.CurrentTurn = NewTurn(newID)
@@ -86,21 +82,12 @@ func (r *Round) NextDrawer(g *Game) *shared.Player {
 	r.CurrentDrawerID = newID
 
 	// Broadcast the change.
-	payload := g.Players[newID]
-	if b, err := utils.CreateMessage("drawingPlayerChanged", payload); err == nil {
-		g.Messenger.BroadcastMessage(b)
-	} else {
-		log.Println("error marshalling message:", err)
-	}
+	g.broadcast(e.DrawingPlayerChanged, e.DrawingPlayerChangedMessage{Player: g.Players[newID]})
 	return g.Players[newID]
 }
 
-func (r *Round) GetCurrentDrawer(players map[string]*shared.Player, playerOrder []string) *shared.Player {
-	if len(playerOrder) == 0 {
-		return nil
-	}
-	currentID := playerOrder[r.CurrentDrawerIdx]
-	return players[currentID]
+func (r *Round) GetCurrentDrawer(players map[string]*shared.Player) *shared.Player {
+	return players[r.CurrentDrawerID]
 }
 
 func (r *Round) MarkPlayerAsDrawn(playerID string) {
@@ -110,8 +97,21 @@ func (r *Round) MarkPlayerAsDrawn(playerID string) {
 	r.PlayersDrawn = append(r.PlayersDrawn, playerID)
 }
 
+// IsOver reports whether every connected player has drawn this round.
 func (r *Round) IsOver(g *Game) bool {
-	return len(r.PlayersDrawn) == len(g.PlayerOrder)
+	_, id := r.nextDrawer(g)
+	return id == ""
+}
+
+// removePlayer keeps CurrentDrawerIdx pointing at the same slot in the order
+// after the player at idx is removed from it.
+func (r *Round) removePlayer(idx, remaining int) {
+	if idx < r.CurrentDrawerIdx {
+		r.CurrentDrawerIdx--
+	}
+	if r.CurrentDrawerIdx >= remaining {
+		r.CurrentDrawerIdx = max(remaining-1, 0)
+	}
 }
 
 func (r *Round) UnmarkAllPlayersAsDrawn() {
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"time"
+	"unicode"
+
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+)
+
+// maxGuessPoints is what a guess made the instant drawing starts is worth.
+const maxGuessPoints = 100
+
+// difficultyMultipliers scale every score earned on a word by its
+// difficulty. Words without a known difficulty count as medium.
+var difficultyMultipliers = map[string]float64{
+	shared.DifficultyEasy:   1,
+	shared.DifficultyMedium: 1.25,
+	shared.DifficultyHard:   1.5,
+}
+
+// DifficultyMultiplier returns the factor applied to points earned on a word
+// of the given difficulty.
+func DifficultyMultiplier(difficulty string) float64 {
+	if m, ok := difficultyMultipliers[difficulty]; ok {
+		return m
+	}
+	return difficultyMultipliers[shared.DifficultyMedium]
+}
+
+// orderBonuses are the extra points for the first, second and third correct
+// guesses of a turn under ScoringOrderedBonus.
+var orderBonuses = []int{50, 30, 10}
+
+// GuessContext describes a correct guess at the moment it is made.
+type GuessContext struct {
+	// Remaining and Duration are the time left on the turn timer and its
+	// total length.
+	Remaining time.Duration
+	Duration  time.Duration
+	// Order is 1 for the first correct guess of the turn, 2 for the second
+	// and so on.
+	Order int
+	// Letters is how many letters the word has; Hidden is how many of them
+	// had not been revealed as hints yet.
+	Letters int
+	Hidden  int
+}
+
+// TurnSummary describes a finished turn from the drawer's point of view.
+type TurnSummary struct {
+	// Guessers is how many players, other than the drawer, took part.
+	Guessers int
+	// Points holds what each correct guesser earned.
+	Points []int
+}
+
+// Scorer decides how many points a turn is worth.
+type Scorer interface {
+	// GuessPoints scores a single correct guess.
+	GuessPoints(guess GuessContext) int
+	// DrawerPoints scores the drawer once the turn has ended.
+	DrawerPoints(turn TurnSummary) int
+}
+
+// NewScorer returns the strategy named by one of the shared.Scoring*
+// constants. Unknown names fall back to ScoringTimeLinear.
+func NewScorer(strategy string) Scorer {
+	switch strategy {
+	case shared.ScoringOrderedBonus:
+		return orderedBonusScorer{}
+	case shared.ScoringDrawerShare:
+		return drawerShareScorer{}
+	case shared.ScoringHintPenalty:
+		return hintPenaltyScorer{}
+	default:
+		return timeLinearScorer{}
+	}
+}
+
+// timeLinearScorer pays guessers in proportion to the time left. The drawer
+// earns nothing.
+type timeLinearScorer struct{}
+
+func (timeLinearScorer) GuessPoints(guess GuessContext) int {
+	if guess.Duration <= 0 {
+		return 0
+	}
+	remaining := min(max(guess.Remaining, 0), guess.Duration)
+	return int(maxGuessPoints * (float64(remaining) / float64(guess.Duration)))
+}
+
+func (timeLinearScorer) DrawerPoints(TurnSummary) int {
+	return 0
+}
+
+// orderedBonusScorer adds a fixed bonus for the first few correct guesses on
+// top of the time-linear score.
+type orderedBonusScorer struct {
+	timeLinearScorer
+}
+
+func (s orderedBonusScorer) GuessPoints(guess GuessContext) int {
+	points := s.timeLinearScorer.GuessPoints(guess)
+	if guess.Order >= 1 && guess.Order <= len(orderBonuses) {
+		points += orderBonuses[guess.Order-1]
+	}
+	return points
+}
+
+// drawerShareScorer scores guessers like timeLinearScorer but gives the drawer
+// half of every correct guess. It is the only strategy that pays the drawer.
+type drawerShareScorer struct {
+	timeLinearScorer
+}
+
+func (drawerShareScorer) DrawerPoints(turn TurnSummary) int {
+	total := 0
+	for _, points := range turn.Points {
+		total += points / 2
+	}
+	return total
+}
+
+// hintPenaltyScorer scales the time-linear score by the share of letters that
+// were still hidden. Since at most half of a word is revealed, a guess keeps
+// at least half of its value.
+type hintPenaltyScorer struct {
+	timeLinearScorer
+}
+
+func (s hintPenaltyScorer) GuessPoints(guess GuessContext) int {
+	points := s.timeLinearScorer.GuessPoints(guess)
+	if guess.Letters == 0 {
+		return points
+	}
+	return points * guess.Hidden / guess.Letters
+}
+
+func (g *Game) scorer() Scorer {
+	return NewScorer(g.Options.Scoring)
+}
+
+// guessContext describes a correct guess made now, in the current turn. The
+// guesser must already be recorded in PlayersGuessedCorrectly.
+func (g *Game) guessContext() GuessContext {
+	guess := GuessContext{
+		Remaining: seconds(g.remainingTime("turnTimer")),
+	}
+	if timer, ok := g.timers["turnTimer"]; ok {
+		guess.Duration = seconds(timer.duration)
+	}
+	for _, correct := range g.CurrentTurn.PlayersGuessedCorrectly {
+		if correct {
+			guess.Order++
+		}
+	}
+	for _, r := range g.CurrentTurn.RevealedLetters {
+		if r == '_' {
+			guess.Hidden++
+		}
+	}
+	if word := g.CurrentTurn.WordToGuess; word != nil {
+		for _, r := range word.Word {
+			if unicode.IsLetter(r) {
+				guess.Letters++
+			}
+		}
+	}
+	return guess
+}
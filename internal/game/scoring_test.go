@@ -0,0 +1,153 @@
+package game_test
+
+import (
+	"testing"
+	"time"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func guessAt(remaining, order, hidden int) g.GuessContext {
+	return g.GuessContext{
+		Remaining: time.Duration(remaining) * time.Second,
+		Duration:  60 * time.Second,
+		Order:     order,
+		Letters:   6,
+		Hidden:    hidden,
+	}
+}
+
+func TestTimeLinearScorer(t *testing.T) {
+	scorer := g.NewScorer(shared.ScoringTimeLinear)
+
+	assert.Equal(t, 100, scorer.GuessPoints(guessAt(60, 1, 6)))
+	assert.Equal(t, 50, scorer.GuessPoints(guessAt(30, 2, 3)))
+	assert.Equal(t, 0, scorer.GuessPoints(guessAt(0, 1, 6)))
+	assert.Equal(t, 0, scorer.GuessPoints(g.GuessContext{}))
+
+	// The drawer earns nothing, however the guessers did.
+	assert.Equal(t, 0, scorer.DrawerPoints(g.TurnSummary{Guessers: 3, Points: []int{80, 40}}))
+	assert.Equal(t, 0, scorer.DrawerPoints(g.TurnSummary{}))
+}
+
+func TestOrderedBonusScorer(t *testing.T) {
+	scorer := g.NewScorer(shared.ScoringOrderedBonus)
+
+	assert.Equal(t, 100, scorer.GuessPoints(guessAt(30, 1, 6)))
+	assert.Equal(t, 80, scorer.GuessPoints(guessAt(30, 2, 6)))
+	assert.Equal(t, 60, scorer.GuessPoints(guessAt(30, 3, 6)))
+	assert.Equal(t, 50, scorer.GuessPoints(guessAt(30, 4, 6)))
+
+	assert.Equal(t, 0, scorer.DrawerPoints(g.TurnSummary{Guessers: 2, Points: []int{100, 20}}))
+}
+
+func TestDrawerShareScorer(t *testing.T) {
+	scorer := g.NewScorer(shared.ScoringDrawerShare)
+
+	assert.Equal(t, 50, scorer.GuessPoints(guessAt(30, 1, 6)))
+
+	// Half of each correct guess, regardless of how many players missed.
+	assert.Equal(t, 60, scorer.DrawerPoints(g.TurnSummary{Guessers: 5, Points: []int{80, 40}}))
+	assert.Equal(t, 0, scorer.DrawerPoints(g.TurnSummary{Guessers: 5}))
+}
+
+func TestHintPenaltyScorer(t *testing.T) {
+	scorer := g.NewScorer(shared.ScoringHintPenalty)
+
+	assert.Equal(t, 60, scorer.GuessPoints(guessAt(36, 1, 6)))
+	assert.Equal(t, 40, scorer.GuessPoints(guessAt(36, 1, 4)))
+	assert.Equal(t, 30, scorer.GuessPoints(guessAt(36, 1, 3)))
+	assert.Equal(t, 60, scorer.GuessPoints(g.GuessContext{Remaining: 36 * time.Second, Duration: 60 * time.Second}))
+
+	assert.Equal(t, 0, scorer.DrawerPoints(g.TurnSummary{Guessers: 2, Points: []int{60}}))
+}
+
+func TestUnknownScoringFallsBackToTimeLinear(t *testing.T) {
+	scorer := g.NewScorer("bogus")
+	assert.Equal(t, 50, scorer.GuessPoints(guessAt(30, 1, 6)))
+}
+
+func TestScoringOption(t *testing.T) {
+	options := shared.DefaultGameOptions()
+	assert.Equal(t, shared.ScoringTimeLinear, options.Scoring)
+
+	options.Scoring = "bogus"
+	var optionsErr *shared.OptionsError
+	if assert.ErrorAs(t, options.Validate(), &optionsErr) {
+		assert.Contains(t, optionsErr.Fields, "scoring")
+	}
+}
+
+func TestOrderedBonusInGame(t *testing.T) {
+	sim := gametest.New(t, shared.GameOptions{
+		MaxPlayers:          4,
+		TurnTimeLimit:       10,
+		RoundLimit:          1,
+		WordSelectTimeLimit: 5,
+		StartCountdown:      3,
+		Scoring:             shared.ScoringOrderedBonus,
+	})
+	sim.Join("drawer")
+	first, second := sim.Join("first"), sim.Join("second")
+
+	sim.StartGame()
+	// The first word offered is easy, so no difficulty multiplier applies.
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+	easy := 0
+	sim.Send(sim.Turn().DrawerID, e.SelectWord, e.SelectWordPayload{Index: &easy})
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+	require.Equal(t, shared.DifficultyEasy, sim.Turn().Word.Difficulty)
+	word := sim.Turn().Word.Word
+	sim.Send(first, e.PlayerGuess, e.PlayerGuessPayload{Guess: word})
+	sim.Send(second, e.PlayerGuess, e.PlayerGuessPayload{Guess: word})
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseTurnResults })
+
+	results := lastMessage[e.TurnResultsMessage](t, sim, e.TurnResults)
+	assert.Equal(t, 150, results.Results[1].Points)
+	assert.Equal(t, 130, results.Results[2].Points)
+	assert.Zero(t, results.DrawerBonus)
+}
+
+func TestDifficultyOptions(t *testing.T) {
+	options := shared.DefaultGameOptions()
+	assert.Equal(t, shared.Difficulties, options.DifficultyMix)
+
+	options.DifficultyMix = []string{shared.DifficultyHard, "impossible"}
+	options.Categories = []string{"Animals", "Animals"}
+	var optionsErr *shared.OptionsError
+	if assert.ErrorAs(t, options.Validate(), &optionsErr) {
+		assert.Contains(t, optionsErr.Fields, "difficultyMix")
+		assert.Contains(t, optionsErr.Fields, "categories")
+	}
+}
+
+func TestHardWordsScoreMore(t *testing.T) {
+	assert.Equal(t, 1.0, g.DifficultyMultiplier(shared.DifficultyEasy))
+	assert.Greater(t, g.DifficultyMultiplier(shared.DifficultyHard), g.DifficultyMultiplier(shared.DifficultyMedium))
+	assert.Equal(t, g.DifficultyMultiplier(shared.DifficultyMedium), g.DifficultyMultiplier(""))
+
+	sim := newTestGame(t, 2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+	hard := 2
+	sim.Send(sim.Turn().DrawerID, e.SelectWord, e.SelectWordPayload{Index: &hard})
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+	require.Equal(t, shared.DifficultyHard, sim.Turn().Word.Difficulty)
+
+	// A guess made the instant drawing starts earns the full 100 points,
+	// scaled by the multiplier.
+	guesser := guessersOf(sim)[0]
+	sim.Send(guesser, e.PlayerGuess, e.PlayerGuessPayload{Guess: sim.Turn().Word.Word})
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseTurnResults })
+	results := lastMessage[e.TurnResultsMessage](t, sim, e.TurnResults)
+	for _, result := range results.Results {
+		if result.PlayerID == guesser {
+			assert.Equal(t, 150, result.Points)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package game_test
+
+import (
+	"fmt"
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadPlayerIDIsIgnored(t *testing.T) {
+	sim := newTestGame(t, 3)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+
+	guessers := guessersOf(sim)
+	sender, victim := guessers[0], guessers[1]
+	word := sim.Turn().Word.Word
+
+	// A guess claiming to come from someone else counts for the sender.
+	msg := fmt.Sprintf(`{"type":"playerGuess","payload":{"playerID":%q,"guess":%q}}`, victim, word)
+	require.NoError(t, sim.Messenger.InjectJSON(sender, []byte(msg)))
+	sim.Settle()
+
+	guessed := sim.Game.GetGameState().Turn.PlayersGuessedCorrectly
+	assert.True(t, guessed[sender])
+	assert.False(t, guessed[victim])
+
+	// State is only ever sent to the player who asked for it.
+	sim.Messenger.Reset()
+	msg = fmt.Sprintf(`{"type":"gameState","payload":{"playerID":%q}}`, victim)
+	require.NoError(t, sim.Messenger.InjectJSON(sender, []byte(msg)))
+	sim.Settle()
+
+	sent := messagesOf(sim, "gameState")
+	require.Len(t, sent, 1)
+	assert.Equal(t, sender, sent[0].To)
+}
+
+func TestErrorsEchoRequestID(t *testing.T) {
+	sim := newTestGame(t, 2)
+	player := sim.Players[1]
+
+	msg := `{"type":"selectWord","requestID":"req-7","payload":{"word":{"word":"Ants"}}}`
+	require.NoError(t, sim.Messenger.InjectJSON(player, []byte(msg)))
+	sim.Settle()
+
+	err := lastError(t, sim, player)
+	assert.Equal(t, e.CodeWrongPhase, err.Code)
+	assert.Equal(t, "req-7", err.RequestID)
+
+	sim.Send(player, e.SelectWord, e.SelectWordPayload{})
+	sim.Settle()
+	assert.Empty(t, lastError(t, sim, player).RequestID)
+}
+
+func TestEventsFromKickedPlayerAreDropped(t *testing.T) {
+	sim := newTestGame(t, 3)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+
+	var victim string
+	for _, id := range guessersOf(sim) {
+		if id != sim.Host() {
+			victim = id
+		}
+	}
+	word := sim.Turn().Word.Word
+
+	// The guess is still queued when the kick is handled.
+	require.NoError(t, sim.Messenger.Inject(sim.Host(), e.KickPlayer, e.KickPlayerPayload{PlayerID: victim}))
+	require.NoError(t, sim.Messenger.Inject(victim, e.PlayerGuess, e.PlayerGuessPayload{Guess: word}))
+	sim.Settle()
+
+	assert.False(t, sim.Finished())
+	assert.Nil(t, sim.Game.GetPlayerByID(victim))
+	assert.False(t, sim.Game.GetGameState().Turn.PlayersGuessedCorrectly[victim])
+}
@@ -0,0 +1,67 @@
+package game_test
+
+import (
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/messaging/messagingtest"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+// flowMessages are the messages that mark game progress. Game state and hint
+// broadcasts are left out since their count depends on timing, not script.
+var flowMessages = []string{
+	"startGameCountdown", "drawingPlayerChanged", "openSelectWordModal",
+	"selectWordTimer", "selectedWord", "turnTimer", "playerGuess", "turnResults",
+	"roundResults", "podium", "gameEnded",
+}
+
+// TestSimulatedGameMessageSequence plays one round with two players. Each
+// drawer picks the first word offered, draws for two seconds, and the other
+// player guesses it.
+func TestSimulatedGameMessageSequence(t *testing.T) {
+	sim := gametest.New(t, shared.GameOptions{
+		MaxPlayers:          4,
+		TurnTimeLimit:       10,
+		RoundLimit:          1,
+		WordSelectTimeLimit: 5,
+		StartCountdown:      3,
+	})
+	sim.JoinN(2)
+
+	sim.StartGame()
+	for turn := 0; turn < 2; turn++ {
+		sim.RunUntil(func() bool {
+			turn := sim.Turn()
+			return turn.Phase == g.PhaseWordSelection && len(turn.SelectableWords) > 0
+		})
+		current := sim.Turn()
+		sim.Send(current.DrawerID, e.SelectWord, e.SelectWordPayload{WordID: current.SelectableWords[0].Id})
+		sim.RunUntil(func() bool { return sim.Turn().Drawing })
+		sim.Step()
+		sim.Step()
+		sim.GuessAll()
+	}
+	sim.PlayToEnd(nil)
+
+	assert.Equal(t, []string{
+		"startGameCountdown",
+		// player0 draws and player1 guesses.
+		"drawingPlayerChanged", "openSelectWordModal", "selectedWord", "turnTimer",
+		"playerGuess", "turnResults",
+		// player1 draws and player0 guesses.
+		"drawingPlayerChanged", "turnTimer", "playerGuess", "turnResults",
+		"roundResults", "podium", "gameEnded",
+	}, messagingtest.Types(sim.Messenger.ReceivedBy("player0"), flowMessages...))
+
+	assert.Equal(t, []string{
+		"startGameCountdown",
+		"drawingPlayerChanged", "turnTimer", "playerGuess", "turnResults",
+		"drawingPlayerChanged", "openSelectWordModal", "selectedWord", "turnTimer",
+		"playerGuess", "turnResults",
+		"roundResults", "podium", "gameEnded",
+	}, messagingtest.Types(sim.Messenger.ReceivedBy("player1"), flowMessages...))
+}
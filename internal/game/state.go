@@ -3,11 +3,16 @@ package game
 import (
 	"encoding/json"
 	"log"
+	"slices"
+	"time"
 
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/Ajstraight619/pictionary-server/internal/utils"
 )
 
-type Status int
+// Status is whether the game is waiting to start, running or over.
+type Status = e.GameStatus
 
 const (
 	NotStarted Status = iota
@@ -15,50 +20,102 @@ const (
 	Finished
 )
 
-type GameState struct {
-	ID              string             `json:"id"`
-	Players         []*shared.Player   `json:"players"`
-	PlayerOrder     []string           `json:"playerOrder"`
-	CurrentDrawerID string             `json:"currentDrawerID"`
-	Options         shared.GameOptions `json:"options"`
-	Status          Status             `json:"status"`
-	Round           *Round             `json:"round"`
-	Turn            *Turn              `json:"turn"`
-	WordToGuess     *shared.Word       `json:"wordToGuess,omitempty"`
-	IsSelectingWord bool               `json:"isSelectingWord"`
-}
+// GameState is a snapshot of the game as sent to players.
+type GameState = e.GameStateMessage
 
+// GetGameState returns a snapshot of the game. It shares no memory with the
+// live state, so it can be read and marshalled from any goroutine. The
+// snapshot includes the word, so it must not be sent to players as is.
 func (g *Game) GetGameState() GameState {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
+	var state GameState
+	g.query(func() { state = g.gameState() })
+	return state
+}
+
+func (g *Game) gameState() GameState {
+	var deadline *time.Time
+	if !g.phaseDeadline.IsZero() {
+		d := g.phaseDeadline
+		deadline = &d
+	}
 	orderedPlayers := make([]*shared.Player, 0, len(g.PlayerOrder))
 	for _, id := range g.PlayerOrder {
 		if player, exists := g.Players[id]; exists {
-			orderedPlayers = append(orderedPlayers, player)
+			orderedPlayers = append(orderedPlayers, clonePlayer(player))
 		}
 	}
 	return GameState{
 		ID:              g.ID,
 		Players:         orderedPlayers,
-		PlayerOrder:     g.PlayerOrder,
+		PlayerOrder:     slices.Clone(g.PlayerOrder),
 		CurrentDrawerID: g.Round.CurrentDrawerID,
 		Options:         g.Options,
 		Status:          g.Status,
-		Round:           g.Round,
-		Turn:            g.CurrentTurn,
+		Phase:           g.Phase,
+		PhaseDeadline:   deadline,
+		Round:           g.Round.state(),
+		Turn:            g.CurrentTurn.state(),
 		IsSelectingWord: g.CurrentTurn.IsSelectingWord,
 	}
 }
 
+// stateFor returns the state as playerID may see it. The word is only shown
+// to the drawer, to players who have guessed it and, once the turn is over,
+// to everyone. Only the drawer sees the words on offer, and only the host
+// sees the custom word list.
+func (g *Game) stateFor(playerID string) GameState {
+	state := g.gameState()
+	if !g.isHost(playerID) {
+		state.Options.CustomWords = nil
+	}
+	if playerID != state.Turn.CurrentDrawerID {
+		state.Turn.SelectableWords = nil
+		if !g.canSeeWord(playerID) {
+			state.Turn.WordToGuess = nil
+		}
+	}
+	return state
+}
+
+// canSeeWord reports whether playerID may know the current word.
+func (g *Game) canSeeWord(playerID string) bool {
+	switch {
+	case playerID == g.CurrentTurn.CurrentDrawerID:
+		return true
+	case g.CurrentTurn.PlayersGuessedCorrectly[playerID]:
+		return true
+	default:
+		return g.Phase == PhaseTurnResults || g.Phase == PhaseRoundResults || g.Phase == PhaseGameOver
+	}
+}
+
+// BroadcastGameState sends the current state to every player.
 func (g *Game) BroadcastGameState() {
-	state := g.GetGameState()
-	b, err := json.Marshal(map[string]any{
-		"type":    "gameState",
-		"payload": state,
-	})
+	g.do(g.broadcastGameState)
+}
+
+// broadcastGameState sends each player their own view of the state.
+func (g *Game) broadcastGameState() {
+	for _, id := range g.PlayerOrder {
+		g.sendGameState(id)
+	}
+}
+
+func (g *Game) sendGameState(playerID string) {
+	b, err := utils.CreateMessage(e.GameState, g.stateFor(playerID))
 	if err != nil {
 		log.Println("error marshalling game state:", err)
+		return
+	}
+	g.Messenger.SendToPlayer(playerID, b)
+}
 
+// broadcast sends a message to every player.
+func (g *Game) broadcast(msgType string, payload any) {
+	b, err := utils.CreateMessage(msgType, payload)
+	if err != nil {
+		log.Printf("error marshalling %s message: %v", msgType, err)
+		return
 	}
 	g.Messenger.BroadcastMessage(b)
 }
@@ -4,105 +4,81 @@ import (
 	"log"
 	"time"
 
-	"github.com/Ajstraight619/pictionary-server/internal/utils"
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
 )
 
 type TimerManager struct {
-	game *Game
+	game  *Game
+	clock clock.Clock
 }
 
-func NewTimerManager(game *Game) *TimerManager {
-	return &TimerManager{game: game}
+func NewTimerManager(game *Game, clk clock.Clock) *TimerManager {
+	return &TimerManager{game: game, clock: clk}
 }
 
 func (tm *TimerManager) StartGameCountdown(timerType string, duration int) {
-	timer := NewTimer(tm.game.ctx, timerType, duration)
+	if !tm.game.setPhase(PhaseCountdown, seconds(duration)) {
+		return
+	}
+	tm.game.broadcastGameState()
+	timer := NewTimer(tm.game, timerType, duration)
 	tm.game.timers[timerType] = timer
 
+	onTick := func(remaining int) {
+		log.Println("Broadcasting game countdown:", remaining)
+		tm.game.broadcast(e.StartGameCountdown, e.TimerMessage{TimeRemaining: remaining})
+	}
 	onFinish := func() {
 		log.Println("Game countdown finished")
-		tm.game.Mu.Lock()
 		tm.game.Status = InProgress
-		tm.game.Mu.Unlock()
-		tm.game.Start()
+		tm.game.start()
 	}
-
 	onCancel := func() {
 		log.Println("Game countdown cancelled")
-		tm.game.Mu.Lock()
 		tm.game.Status = NotStarted
-		tm.game.Mu.Unlock()
-		tm.game.CancelTimer(timerType)
+		tm.game.setPhase(PhaseLobby, 0)
+		tm.game.broadcastGameState()
 	}
 
-	go func() {
-		for remaining := range timer.StartCountdown(onFinish, onCancel) {
-			msgType := "startGameCountdown"
-			payload := map[string]interface{}{
-				"timeRemaining": remaining,
-			}
-			log.Println("Broadcasting game countdown:", remaining)
-
-			b, err := utils.CreateMessage(msgType, payload)
-			if err != nil {
-				log.Println("error marshalling message")
-				return
-			}
-			tm.game.Messenger.BroadcastMessage(b)
-		}
-	}()
+	timer.StartCountdown(onTick, onFinish, onCancel)
 }
 
 func (tm *TimerManager) StartTurnTimer(playerID string) {
-	timer := NewTimer(tm.game.ctx, "turnTimer", 10)
+	turn := tm.game.CurrentTurn
+	timer := NewTimer(tm.game, "turnTimer", tm.game.Options.TurnTimeLimit)
 	tm.game.timers["turnTimer"] = timer
 
-	onCancel := func() {
-		tm.game.FlowSignal <- TurnEnded
+	onTick := func(remaining int) {
+		tm.game.broadcast(e.TurnTimer, e.TimerMessage{TimeRemaining: remaining})
+		turn.BroadcastRevealedLetter(tm.game, remaining)
 	}
-	onFinish := func() {
-		tm.game.FlowSignal <- TurnEnded
+	// Cancelling the turn timer ends the turn early.
+	onEnd := func() {
+		tm.game.signal(TurnEnded)
 	}
-	go func() {
-		for remaining := range timer.StartCountdown(onFinish, onCancel) {
-			msgType := "turnTimer"
-			payload := map[string]interface{}{
-				"timeRemaining": remaining,
-			}
-			if b, err := utils.CreateMessage(msgType, payload); err == nil {
-				tm.game.Messenger.BroadcastMessage(b)
-			} else {
-				log.Println("error marshalling turnTimer message:", err)
-			}
-		}
-	}()
+
+	timer.StartCountdown(onTick, onEnd, onEnd)
 }
 
 func (tm *TimerManager) StartWordSelectionTimer(playerID string) {
-	timer := NewTimer(tm.game.ctx, "selectWordTimer", 8)
+	timer := NewTimer(tm.game, "selectWordTimer", tm.game.Options.WordSelectTimeLimit)
 	tm.game.timers["selectWordTimer"] = timer
 	log.Println("Word selection timer started.")
 
-	go func() {
-		// To control pacing of game. Small delays in between different game actions and state updates.
-		time.Sleep(1 * time.Second)
-		for remaining := range timer.StartCountdown(
-			func() {
-				tm.game.handleTimerExpiration()
-			},
-			func() {
-				log.Println("Word selection cancelled. Timer stopped.")
-			},
-		) {
-			payload := map[string]interface{}{
-				"timeRemaining": remaining,
-			}
-			if b, err := utils.CreateMessage("selectWordTimer", payload); err == nil {
-				tm.game.Messenger.SendToPlayer(playerID, b)
-			} else {
-				log.Println("error marshalling selectWordTimer message:", err)
-			}
-		}
+	onTick := func(remaining int) {
+		tm.game.sendTo(playerID, e.SelectWordTimer, e.TimerMessage{TimeRemaining: remaining})
+	}
+	onFinish := func() {
+		tm.game.handleTimerExpiration()
 		log.Println("Word selection timer ended.")
-	}()
+	}
+	onCancel := func() {
+		log.Println("Word selection cancelled. Timer stopped.")
+	}
+
+	// To control pacing of game. Small delays in between different game actions and state updates.
+	tm.game.after(1*time.Second, func() {
+		timer.StartCountdown(onTick, onFinish, onCancel)
+	})
 }
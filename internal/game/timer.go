@@ -1,20 +1,27 @@
 package game
 
 import (
-	"context"
 	"log"
-	"sync"
 	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
 )
 
+// Timer counts down once a second. Each tick is scheduled with after, so the
+// remaining time and the callbacks are only ever touched on the Run
+// goroutine.
 type Timer struct {
 	Type      string
+	game      *Game
 	duration  int
 	remaining int
 	isRunning bool
-	mu        sync.RWMutex
-	ctx       context.Context // Store the context
-	cancel    context.CancelFunc
+	stopped   bool
+	// next is the pending tick.
+	next     clock.Timer
+	onTick   func(remaining int)
+	onFinish func()
+	onCancel func()
 }
 
 type TimerMessage struct {
@@ -22,95 +29,92 @@ type TimerMessage struct {
 	Remaining int    `json:"remaining"`
 }
 
-func NewTimer(ctx context.Context, timerType string, duration int) *Timer {
-
-	timerCtx, cancel := context.WithCancel(ctx)
+func NewTimer(g *Game, timerType string, duration int) *Timer {
 	return &Timer{
 		Type:      timerType,
+		game:      g,
 		duration:  duration,
 		remaining: duration,
 		isRunning: false,
-		ctx:       timerCtx, // Store the context
-		cancel:    cancel,
 	}
-
 }
 
-func (t *Timer) StartCountdown(onFinish func(), onCancel func()) <-chan int {
-	t.mu.Lock()
+// StartCountdown starts ticking. onTick receives the remaining seconds after
+// every tick; onFinish runs one tick after the countdown reaches zero. All
+// callbacks run on the Run goroutine.
+func (t *Timer) StartCountdown(onTick func(remaining int), onFinish func(), onCancel func()) {
+	if t.stopped {
+		return
+	}
 	t.isRunning = true
 	t.remaining = t.duration
-	t.mu.Unlock()
-
-	tickCh := make(chan int, 1)
+	t.onTick = onTick
+	t.onFinish = onFinish
+	t.onCancel = onCancel
+	t.schedule()
+}
 
-	go func() {
-		ticker := time.NewTicker(time.Second)
-		defer ticker.Stop()
+func (t *Timer) schedule() {
+	t.next = t.game.after(time.Second, t.tick)
+}
 
-		for {
-			select {
-			case <-ticker.C:
-				t.mu.Lock()
-				if t.remaining <= 0 {
-					t.isRunning = false
-					t.mu.Unlock()
-					close(tickCh)
-					if onFinish != nil {
-						log.Println("Timer finished")
-						onFinish()
-					}
-					return
-				}
-				t.remaining--
-				remaining := t.remaining
-				t.mu.Unlock()
-				select {
-				case tickCh <- remaining:
-				default:
-				}
-			case <-t.ctx.Done():
-				// Timer was cancelled.
-				t.mu.Lock()
-				t.isRunning = false
-				t.mu.Unlock()
-				close(tickCh)
-				if onCancel != nil {
-					onCancel()
-				}
-				return
-			}
+func (t *Timer) tick() {
+	if t.stopped {
+		// A tick that was already queued when the timer stopped.
+		return
+	}
+	if t.remaining <= 0 {
+		t.stop()
+		if current, ok := t.game.timers[t.Type]; ok && current == t {
+			delete(t.game.timers, t.Type)
+		}
+		if t.onFinish != nil {
+			log.Println("Timer finished")
+			t.onFinish()
 		}
-	}()
+		return
+	}
+	t.remaining--
+	if t.onTick != nil {
+		t.onTick(t.remaining)
+	}
+	if !t.stopped {
+		t.schedule()
+	}
+}
 
-	return tickCh
+// stop halts the timer without running any callback.
+func (t *Timer) stop() {
+	t.stopped = true
+	t.isRunning = false
+	if t.next != nil {
+		t.next.Stop()
+	}
 }
 
-func (g *Game) CancelTimer(timerType string) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+func (g *Game) cancelTimer(timerType string) {
 	if timer, exists := g.timers[timerType]; exists {
 		log.Printf("Cancelling timer %s", timerType)
-		timer.Cancel()
 		delete(g.timers, timerType)
-
+		timer.Cancel()
 	}
 }
 
+// Cancel stops the timer and runs its cancel callback. A timer that has not
+// started counting down yet is cancelled as well, so its countdown never
+// begins.
 func (t *Timer) Cancel() {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	if t.cancel != nil && t.isRunning {
-		t.cancel()
-		t.cancel = nil
-		t.isRunning = false
-		t.remaining = 0
+	if t.stopped {
+		return
+	}
+	t.stop()
+	t.remaining = 0
+	if t.onCancel != nil {
+		t.onCancel()
 	}
 }
 
-func (g *Game) GetRemainingTime(timerType string) int {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
+func (g *Game) remainingTime(timerType string) int {
 	if timer, exists := g.timers[timerType]; exists {
 		return timer.remaining
 	}
@@ -2,20 +2,14 @@ package game
 
 import (
 	"log"
+	"maps"
 	"math"
-	"math/rand"
 	"slices"
 	"time"
+	"unicode"
 
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
-	"github.com/Ajstraight619/pictionary-server/internal/utils"
-)
-
-type TurnPhase int
-
-const (
-	PhaseWordSelection TurnPhase = iota
-	PhaseDrawing
 )
 
 type Turn struct {
@@ -23,9 +17,18 @@ type Turn struct {
 	WordToGuess             *shared.Word    `json:"wordToGuess,omitempty"`
 	RevealedLetters         []rune          `json:"revealedLetters"`
 	PlayersGuessedCorrectly map[string]bool `json:"playersGuessedCorrectly"`
-	Phase                   TurnPhase       `json:"phase"`
 	IsSelectingWord         bool            `json:"isSelectingWord"`
 	SelectableWords         []shared.Word   `json:"selectableWords,omitempty"`
+	// startedAt is when drawing began. points and guessTimes record what
+	// each player earned this turn and how long they took to guess.
+	startedAt  time.Time
+	points     map[string]int
+	guessTimes map[string]time.Duration
+	// offered holds the words the drawer may choose from. Unlike
+	// SelectableWords it is kept once a word is chosen, so a late selection
+	// can still be matched. rerolls counts the times it was replaced.
+	offered []shared.Word
+	rerolls int
 }
 
 func InitTurn() *Turn {
@@ -34,8 +37,9 @@ func InitTurn() *Turn {
 		PlayersGuessedCorrectly: make(map[string]bool),
 		RevealedLetters:         make([]rune, 0),
 		WordToGuess:             nil,
-		Phase:                   PhaseWordSelection,
 		SelectableWords:         make([]shared.Word, 0),
+		points:                  make(map[string]int),
+		guessTimes:              make(map[string]time.Duration),
 	}
 }
 
@@ -45,90 +49,130 @@ func NewTurn(playerID string) *Turn {
 		PlayersGuessedCorrectly: make(map[string]bool),
 		RevealedLetters:         make([]rune, 0),
 		WordToGuess:             nil,
-		Phase:                   PhaseWordSelection,
 		SelectableWords:         make([]shared.Word, 0),
+		points:                  make(map[string]int),
+		guessTimes:              make(map[string]time.Duration),
+	}
+}
+
+func (t *Turn) state() e.TurnState {
+	state := e.TurnState{
+		CurrentDrawerID:         t.CurrentDrawerID,
+		RevealedLetters:         slices.Clone(t.RevealedLetters),
+		PlayersGuessedCorrectly: maps.Clone(t.PlayersGuessedCorrectly),
+		IsSelectingWord:         t.IsSelectingWord,
+		SelectableWords:         slices.Clone(t.SelectableWords),
 	}
+	if t.WordToGuess != nil {
+		word := *t.WordToGuess
+		state.WordToGuess = &word
+	}
+	return state
 }
 
 func (t *Turn) Start(g *Game, playerID string) {
 	log.Println("Turn started")
-	revealedLetters := make([]rune, len(g.CurrentTurn.WordToGuess.Word))
-	for i := range revealedLetters {
-		revealedLetters[i] = '_'
+	// Spaces and punctuation are shown from the start; only letters are hidden.
+	letters := []rune(g.CurrentTurn.WordToGuess.Word)
+	revealedLetters := make([]rune, len(letters))
+	for i, r := range letters {
+		if unicode.IsLetter(r) {
+			revealedLetters[i] = '_'
+		} else {
+			revealedLetters[i] = r
+		}
 	}
 	t.RevealedLetters = revealedLetters
 	t.CurrentDrawerID = playerID
+	t.startedAt = g.clock.Now()
 	g.TimerManager.StartTurnTimer(playerID)
 }
 
+// BroadcastRevealedLetter reveals hint letters on a fixed schedule across the
+// turn. It is called on every turn timer tick and only broadcasts when a new
+// letter is revealed. At most half of the letters are ever given away.
 func (t *Turn) BroadcastRevealedLetter(g *Game, timeRemaining int) {
+	if t.WordToGuess == nil {
+		return
+	}
 	letters := []rune(t.WordToGuess.Word)
-	totalLetters := len(letters)
 	turnTimeLimit := g.Options.TurnTimeLimit
 
-	elapsedTime := turnTimeLimit - timeRemaining
-
-	letterInterval := float64(turnTimeLimit) / float64(totalLetters)
-	targetCount := min(max(int(math.Ceil(float64(elapsedTime)/letterInterval)), 1), totalLetters)
-
-	currentRevealed := 0
-	for _, r := range t.RevealedLetters {
-		if r != '_' {
+	unrevealedIndices := make([]int, 0, len(letters))
+	totalLetters, currentRevealed := 0, 0
+	for i, r := range letters {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		totalLetters++
+		if t.RevealedLetters[i] == '_' {
+			unrevealedIndices = append(unrevealedIndices, i)
+		} else {
 			currentRevealed++
 		}
 	}
 
-	if currentRevealed >= targetCount {
+	maxReveals := totalLetters / 2
+	if maxReveals == 0 || turnTimeLimit <= 0 {
 		return
 	}
 
-	lettersToReveal := targetCount - currentRevealed
+	// Space reveals evenly so the last hint lands before the turn ends.
+	elapsedTime := turnTimeLimit - timeRemaining
+	letterInterval := float64(turnTimeLimit) / float64(maxReveals+1)
+	targetCount := min(int(math.Floor(float64(elapsedTime)/letterInterval)), maxReveals)
 
-	unrevealedIndices := make([]int, 0, totalLetters)
-	for i, r := range t.RevealedLetters {
-		if r == '_' {
-			unrevealedIndices = append(unrevealedIndices, i)
-		}
+	if currentRevealed >= targetCount {
+		return
 	}
 
-	randSource := rand.New(rand.NewSource(time.Now().UnixNano()))
+	lettersToReveal := targetCount - currentRevealed
+
 	for i := 0; i < lettersToReveal && len(unrevealedIndices) > 0; i++ {
-		randIdx := randSource.Intn(len(unrevealedIndices))
+		randIdx := g.rand.Intn(len(unrevealedIndices))
 		indexToReveal := unrevealedIndices[randIdx]
 		t.RevealedLetters[indexToReveal] = letters[indexToReveal]
 		// Remove the index from the slice
 		unrevealedIndices = slices.Delete(unrevealedIndices, randIdx, randIdx+1)
 	}
-
 	// Broadcast the updated revealed letters to all players.
-	if b, err := utils.CreateMessage("revealedLetter", t.RevealedLetters); err == nil {
-		g.Messenger.BroadcastMessage(b)
-	} else {
-		log.Println("error marshalling revealedLetter message:", err)
-	}
+	g.broadcast(e.RevealedLetter, e.RevealedLetterMessage{
+		RevealedLetters: slices.Clone(t.RevealedLetters),
+	})
 }
 
+// End awards the drawer's bonus and reveals the word. After a short pause the
+// next turn starts, or the round ends if everyone has drawn.
 func (t *Turn) End(g *Game) {
 	log.Println("Turn ended")
-	g.ClearDrawingPlayers()
+	g.clearDrawingPlayers()
 	g.Round.MarkPlayerAsDrawn(t.CurrentDrawerID)
-	g.Mu.Lock()
-	roundComplete := len(g.Round.PlayersDrawn) == len(g.PlayerOrder)
-	g.Mu.Unlock()
-	g.setWord(nil)
-	g.BroadcastGameState()
-	if roundComplete {
-		log.Println("Round is over signalling round ended ")
-		g.FlowSignal <- RoundEnded
-	} else {
-		g.Round.NextDrawer(g)
-		g.FlowSignal <- TurnStarted
-	}
+	t.awardDrawerBonus(g)
+	g.Canvas.Reset()
+	g.broadcastTurnResults(t)
+	g.broadcastGameState()
+
+	g.after(turnResultsDuration, func() {
+		if g.Phase != PhaseTurnResults || g.CurrentTurn != t {
+			return
+		}
+		if g.Round.IsOver(g) {
+			log.Println("Round is over signalling round ended ")
+			g.signal(RoundEnded)
+		} else if g.Round.NextDrawer(g) != nil {
+			g.signal(TurnStarted)
+		}
+	})
 }
 
-func (t *Turn) allGuessedCorrectly() bool {
-	for _, guessedCorrectly := range t.PlayersGuessedCorrectly {
-		if !guessedCorrectly {
+// allGuessedCorrectly reports whether every connected guesser has guessed the
+// word. Disconnected players are not waited on.
+func (t *Turn) allGuessedCorrectly(players map[string]*shared.Player) bool {
+	for id, player := range players {
+		if id == t.CurrentDrawerID || !player.Connected {
+			continue
+		}
+		if !t.PlayersGuessedCorrectly[id] {
 			return false
 		}
 	}
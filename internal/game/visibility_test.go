@@ -0,0 +1,91 @@
+package game_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stateSeenBy returns the last game state sent to playerID.
+func stateSeenBy(t *testing.T, sim *gametest.Simulator, playerID string) g.GameState {
+	t.Helper()
+	msg, ok := sim.Messenger.Last(playerID, "gameState")
+	require.True(t, ok, "no gameState sent to %s", playerID)
+	var state g.GameState
+	require.NoError(t, json.Unmarshal(msg.Payload, &state))
+	return state
+}
+
+// guessersOf returns the players other than the current drawer.
+func guessersOf(sim *gametest.Simulator) []string {
+	drawer := sim.Turn().DrawerID
+	var guessers []string
+	for _, id := range sim.Players {
+		if id != drawer {
+			guessers = append(guessers, id)
+		}
+	}
+	return guessers
+}
+
+func TestStateHidesWordFromGuessers(t *testing.T) {
+	sim := newTestGame(t, 3)
+	sim.StartGame()
+
+	sim.RunUntil(func() bool {
+		return sim.Phase() == g.PhaseWordSelection && len(sim.Turn().SelectableWords) > 0
+	})
+	drawer, guessers := sim.Turn().DrawerID, guessersOf(sim)
+	assert.NotEmpty(t, stateSeenBy(t, sim, drawer).Turn.SelectableWords)
+	assert.Empty(t, stateSeenBy(t, sim, guessers[0]).Turn.SelectableWords)
+
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+	word := sim.Turn().Word
+	assert.Equal(t, word, stateSeenBy(t, sim, drawer).Turn.WordToGuess)
+	for _, id := range guessers {
+		assert.Nil(t, stateSeenBy(t, sim, id).Turn.WordToGuess)
+	}
+
+	// Guessing reveals the word to that player only.
+	sim.Send(guessers[0], e.PlayerGuess, e.PlayerGuessPayload{Guess: word.Word})
+	sim.Settle()
+	assert.Equal(t, word, stateSeenBy(t, sim, guessers[0]).Turn.WordToGuess)
+	assert.Nil(t, stateSeenBy(t, sim, guessers[1]).Turn.WordToGuess)
+
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseTurnResults })
+	assert.Equal(t, word, stateSeenBy(t, sim, guessers[1]).Turn.WordToGuess)
+}
+
+func TestCorrectGuessersChatPrivately(t *testing.T) {
+	sim := newTestGame(t, 3)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+
+	drawer, guessers := sim.Turn().DrawerID, guessersOf(sim)
+	word := sim.Turn().Word.Word
+	sim.Send(guessers[0], e.PlayerGuess, e.PlayerGuessPayload{Guess: word})
+	sim.Settle()
+	sim.Messenger.Reset()
+
+	sim.Send(guessers[0], e.PlayerGuess, e.PlayerGuessPayload{Guess: "it was " + word})
+	sim.Settle()
+
+	for _, msg := range sim.Messenger.Messages() {
+		if msg.Type != "playerGuess" {
+			continue
+		}
+		assert.NotEmpty(t, msg.To, "chat from a correct guesser was broadcast")
+		assert.NotEqual(t, guessers[1], msg.To)
+	}
+	_, ok := sim.Messenger.Last(drawer, "playerGuess")
+	assert.True(t, ok, "drawer did not receive the message")
+	_, ok = sim.Messenger.Last(guessers[0], "playerGuess")
+	assert.True(t, ok, "sender did not receive their own message")
+	_, ok = sim.Messenger.Last(guessers[1], "playerGuess")
+	assert.False(t, ok, "a player still guessing received the message")
+}
@@ -1,113 +1,346 @@
 package game
 
 import (
+	"errors"
 	"log"
 	"math/rand"
+	"slices"
 	"time"
 
 	"github.com/Ajstraight619/pictionary-server/internal/db"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
-	"github.com/Ajstraight619/pictionary-server/internal/utils"
 )
 
 type WordSelector struct {
 	game *Game
 }
 
-type WordSelectionPayload struct {
-	IsSelectingWord bool          `json:"isSelectingWord"`
-	SelectableWords []shared.Word `json:"selectableWords"`
-}
+// wordChoices is how many words the drawer is offered, and maxRerolls how
+// many times per turn they may swap them for new ones.
+const (
+	wordChoices = 3
+	maxRerolls  = 1
+)
 
 func NewWordSelector(game *Game) *WordSelector {
 	return &WordSelector{game: game}
 }
 
+// SelectWord offers the drawer words to choose from. If there are none to
+// offer, the turn is skipped.
 func (ws *WordSelector) SelectWord() {
 	ws.game.setIsSelectingWord(true)
-	if err := ws.game.setRandomWords(3); err != nil {
-		log.Println("error getting random words:", err)
+	if err := ws.game.setRandomWords(wordChoices); err != nil {
+		log.Println("error getting random words, skipping turn:", err)
+		ws.game.signal(TurnEnded)
 		return
 	}
 
-	selectionPayload := WordSelectionPayload{
-		IsSelectingWord: true,
-		SelectableWords: ws.game.CurrentTurn.SelectableWords,
-	}
-
-	b, err := utils.CreateMessage("openSelectWordModal", selectionPayload)
-	if err != nil {
-		log.Println("error marshalling message:", err)
-		return
-	}
-	currentDrawer := ws.game.Round.GetCurrentDrawer(ws.game.Players, ws.game.PlayerOrder)
+	currentDrawer := ws.game.Round.GetCurrentDrawer(ws.game.Players)
 	if currentDrawer == nil {
 		log.Println("No current drawer found.")
 		return
 	}
-	ws.game.Messenger.SendToPlayer(currentDrawer.ID, b)
-	ws.game.BroadcastGameState()
+	ws.game.sendWordChoices(currentDrawer.ID)
+	ws.game.broadcastGameState()
 	ws.game.TimerManager.StartWordSelectionTimer(currentDrawer.ID)
 }
 
 func (g *Game) setWord(word *shared.Word) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
 	g.CurrentTurn.WordToGuess = word
 }
 
 func (g *Game) setIsSelectingWord(selecting bool) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
 	g.CurrentTurn.IsSelectingWord = selecting
 }
 
-func (g *Game) setRandomWords(n int) error {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-	words, err := db.GetRandomWords(n)
+// errNoWords means no words matching the game's options are left to offer.
+var errNoWords = errors.New("no words available")
+
+// setRandomWords offers the drawer up to n new words. If there are none, the
+// current offer is kept and errNoWords is returned.
+func (g *Game) setRandomWords(n int, exclude ...uint64) error {
+	words, err := g.pickWords(n, exclude)
 	if err != nil {
 		return err
 	}
+	if len(words) == 0 {
+		return errNoWords
+	}
 	g.CurrentTurn.SelectableWords = words
+	g.CurrentTurn.offered = words
+	g.UsedWords = append(g.UsedWords, words...)
 	return nil
 }
 
+// pickWords draws n words for the drawer to choose from, following the
+// game's word source. Words already offered in this game are skipped until
+// none are left. exclude lists words that must not be offered even then.
+//
+// Custom-only games draw from the custom list alone. Mixed games draw every
+// choice from the custom list and the word bank together.
+func (g *Game) pickWords(n int, exclude []uint64) ([]shared.Word, error) {
+	exclude = slices.Clone(exclude)
+	used := slices.Clone(exclude)
+	for _, w := range g.UsedWords {
+		used = append(used, w.Id)
+	}
+	custom := g.customWords()
+
+	words := make([]shared.Word, 0, n)
+	for i := range n {
+		var word shared.Word
+		var found bool
+		var err error
+		switch g.Options.WordSource {
+		case shared.WordSourceCustom:
+			word, found = pickCustomWord(g.rand, custom, used, exclude, true)
+		case shared.WordSourceMixed:
+			word, found, err = g.pickMixedWord(i, custom, used, exclude)
+		default:
+			word, found, err = g.pickBankWord(i, used, exclude)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			break
+		}
+		words = append(words, word)
+		used = append(used, word.Id)
+		exclude = append(exclude, word.Id)
+	}
+	return words, nil
+}
+
+// pickBankWord draws a word from the enabled categories of the word bank at
+// the i-th difficulty of the mix. When that difficulty has run out of unused
+// words, any unused word is taken instead, and once those are all used too,
+// words may repeat.
+func (g *Game) pickBankWord(i int, used, exclude []uint64) (shared.Word, bool, error) {
+	mix := g.Options.DifficultyMix
+	if len(mix) == 0 {
+		mix = shared.Difficulties
+	}
+	fallbacks := []db.WordFilter{
+		{Categories: g.Options.Categories, Difficulty: mix[i%len(mix)], Exclude: used},
+		{Categories: g.Options.Categories, Exclude: used},
+		{Categories: g.Options.Categories, Exclude: exclude},
+	}
+	for j, filter := range fallbacks {
+		found, err := db.GetRandomWords(g.rand, 1, filter)
+		if err != nil {
+			return shared.Word{}, false, err
+		}
+		if len(found) > 0 {
+			if j == len(fallbacks)-1 {
+				log.Printf("pickBankWord: every word has been used in game %s, repeating %q", g.ID, found[0].Word)
+			}
+			return found[0], true, nil
+		}
+	}
+	return shared.Word{}, false, nil
+}
+
+// pickMixedWord draws a word from the custom list and the word bank as one
+// pool: a custom word is drawn with the chance that a random unused word in
+// the pool is custom. Bank words still follow the difficulty mix. Once both
+// have run out, words may repeat, bank words first.
+func (g *Game) pickMixedWord(i int, custom []shared.Word, used, exclude []uint64) (shared.Word, bool, error) {
+	unused := 0
+	for _, w := range custom {
+		if !slices.Contains(used, w.Id) {
+			unused++
+		}
+	}
+	bank, err := db.CountWords(db.WordFilter{Categories: g.Options.Categories, Exclude: used})
+	if err != nil {
+		return shared.Word{}, false, err
+	}
+	if unused > 0 && g.rand.Intn(unused+bank) < unused {
+		word, found := pickCustomWord(g.rand, custom, used, exclude, false)
+		return word, found, nil
+	}
+	word, found, err := g.pickBankWord(i, used, exclude)
+	if err != nil || found {
+		return word, found, err
+	}
+	word, found = pickCustomWord(g.rand, custom, used, exclude, true)
+	return word, found, nil
+}
+
+// customWordIDBase numbers custom words above any word bank ID, so words of
+// both kinds can be told apart and excluded by ID.
+const customWordIDBase uint64 = 1 << 40
+
+// CustomCategory is the category of words from a game's custom list.
+const CustomCategory = "Custom"
+
+// customWords turns the game's custom word list into words. Their IDs are
+// stable as long as the list does not change.
+func (g *Game) customWords() []shared.Word {
+	words := make([]shared.Word, len(g.Options.CustomWords))
+	for i, word := range g.Options.CustomWords {
+		words[i] = shared.Word{
+			Id:         customWordIDBase + uint64(i),
+			Word:       word,
+			Category:   CustomCategory,
+			Difficulty: shared.DifficultyMedium,
+		}
+	}
+	return words
+}
+
+// pickCustomWord draws a random custom word that has not been used, using
+// rng. If repeat is set and every word has been used, any word not in exclude
+// will do.
+func pickCustomWord(rng *rand.Rand, custom []shared.Word, used, exclude []uint64, repeat bool) (shared.Word, bool) {
+	pick := func(skip []uint64) (shared.Word, bool) {
+		var candidates []shared.Word
+		for _, w := range custom {
+			if !slices.Contains(skip, w.Id) {
+				candidates = append(candidates, w)
+			}
+		}
+		if len(candidates) == 0 {
+			return shared.Word{}, false
+		}
+		return candidates[rng.Intn(len(candidates))], true
+	}
+	if word, ok := pick(used); ok || !repeat {
+		return word, ok
+	}
+	return pick(exclude)
+}
+
+func (g *Game) sendWordChoices(drawerID string) {
+	g.sendTo(drawerID, e.OpenSelectWordModal, e.OpenSelectWordModalMessage{
+		IsSelectingWord: true,
+		SelectableWords: g.CurrentTurn.SelectableWords,
+		RerollsLeft:     maxRerolls - g.CurrentTurn.rerolls,
+	})
+}
+
+// offeredWord finds the word a selectWord payload refers to among the words
+// offered this turn.
+func (t *Turn) offeredWord(pt e.SelectWordPayload) (shared.Word, bool) {
+	switch {
+	case pt.Index != nil:
+		if *pt.Index < 0 || *pt.Index >= len(t.offered) {
+			return shared.Word{}, false
+		}
+		return t.offered[*pt.Index], true
+	case pt.WordID != 0:
+		return findWord(t.offered, func(w shared.Word) bool { return w.Id == pt.WordID })
+	case pt.Word != nil && pt.Word.Id != 0:
+		return findWord(t.offered, func(w shared.Word) bool { return w.Id == pt.Word.Id })
+	case pt.Word != nil:
+		return findWord(t.offered, func(w shared.Word) bool { return w.Word == pt.Word.Word })
+	default:
+		return shared.Word{}, false
+	}
+}
+
+func findWord(words []shared.Word, match func(shared.Word) bool) (shared.Word, bool) {
+	for _, w := range words {
+		if match(w) {
+			return w, true
+		}
+	}
+	return shared.Word{}, false
+}
+
+// selectWord lets the drawer choose one of the offered words. Choosing the
+// word that was already picked, for example by the timer a moment earlier,
+// does nothing, so a retried or racing selection is harmless.
+func (g *Game) selectWord(playerID string, pt e.SelectWordPayload) {
+	if playerID != g.CurrentTurn.CurrentDrawerID {
+		g.sendError(playerID, e.SelectWord, e.CodeNotYourTurn, "Only the drawer can choose the word")
+		return
+	}
+	word, ok := g.CurrentTurn.offeredWord(pt)
+	if !ok {
+		g.sendError(playerID, e.SelectWord, e.CodeWordNotOffered, "That word was not offered")
+		return
+	}
+	if chosen := g.CurrentTurn.WordToGuess; chosen != nil {
+		if chosen.Id != word.Id {
+			g.sendError(playerID, e.SelectWord, e.CodeAlreadySelected, "A word has already been selected")
+		}
+		return
+	}
+
+	log.Printf("Word selected manually: %s", word.Word)
+	g.cancelTimer("selectWordTimer")
+	g.chooseWord(word)
+	g.signal(TurnStarted)
+}
+
+// chooseWord makes word the word to guess and tells the drawer.
+func (g *Game) chooseWord(word shared.Word) {
+	g.setWord(&word)
+	g.setIsSelectingWord(false)
+	g.clearSelectableWords()
+
+	g.sendTo(g.CurrentTurn.CurrentDrawerID, e.SelectedWord, e.SelectedWordMessage{
+		Word:            g.CurrentTurn.WordToGuess,
+		IsSelectingWord: false,
+	})
+	g.broadcastGameState()
+}
+
+// rerollWords swaps the offered words for new ones. The selection timer
+// keeps running.
+func (g *Game) rerollWords(playerID string) {
+	turn := g.CurrentTurn
+	switch {
+	case playerID != turn.CurrentDrawerID:
+		g.sendError(playerID, e.RerollWords, e.CodeNotYourTurn, "Only the drawer can reroll the words")
+		return
+	case turn.WordToGuess != nil:
+		g.sendError(playerID, e.RerollWords, e.CodeAlreadySelected, "A word has already been selected")
+		return
+	case turn.rerolls >= maxRerolls:
+		g.sendError(playerID, e.RerollWords, e.CodeNoRerollsLeft, "No rerolls left this turn")
+		return
+	}
+
+	exclude := make([]uint64, 0, len(turn.offered))
+	for _, w := range turn.offered {
+		exclude = append(exclude, w.Id)
+	}
+	if err := g.setRandomWords(wordChoices, exclude...); err != nil {
+		log.Println("error getting random words:", err)
+		g.sendError(playerID, e.RerollWords, e.CodeNoWordsLeft, "There are no other words to offer")
+		return
+	}
+	turn.rerolls++
+	g.sendWordChoices(playerID)
+	g.sendGameState(playerID)
+}
+
 func (g *Game) clearSelectableWords() {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
 	g.CurrentTurn.SelectableWords = []shared.Word{}
 }
 
 func (g *Game) handleTimerExpiration() {
 	if len(g.CurrentTurn.SelectableWords) > 0 {
-		g.Mu.Lock()
-		randomIndex := rand.Intn(len(g.CurrentTurn.SelectableWords))
+		randomIndex := g.rand.Intn(len(g.CurrentTurn.SelectableWords))
 		randomWord := g.CurrentTurn.SelectableWords[randomIndex]
 		log.Printf("Timer finished. Automatically selecting word: %s", randomWord.Word)
-		g.Mu.Unlock()
 
-		g.setWord(&randomWord)
-		g.setIsSelectingWord(false)
-		g.clearSelectableWords()
-
-		selectWordPayload := map[string]interface{}{
-			"word":            g.CurrentTurn.WordToGuess,
-			"isSelectingWord": false,
-		}
-
-		b, err := utils.CreateMessage("selectedWord", selectWordPayload)
-		if err != nil {
-			log.Println("error marshalling selectedWord message:", err)
-			return
-		}
-		currentDrawer := g.Round.GetCurrentDrawer(g.Players, g.PlayerOrder)
-		g.Messenger.SendToPlayer(currentDrawer.ID, b)
-		g.BroadcastGameState()
-		time.AfterFunc(1*time.Second, func() {
-			g.FlowSignal <- TurnStarted
+		g.chooseWord(randomWord)
+		turn, word, drawerID := g.CurrentTurn, g.CurrentTurn.WordToGuess, g.CurrentTurn.CurrentDrawerID
+		g.after(1*time.Second, func() {
+			// The drawer may have left in the meantime, ending the turn.
+			if g.CurrentTurn == turn && g.Phase == PhaseWordSelection &&
+				turn.WordToGuess == word && turn.CurrentDrawerID == drawerID {
+				g.signal(TurnStarted)
+			}
 		})
 	} else {
-		log.Println("Timer finished but no selectable words available.")
+		log.Println("Timer finished but no selectable words available, skipping turn")
+		g.signal(TurnEnded)
 	}
 }
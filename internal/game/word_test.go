@@ -0,0 +1,197 @@
+package game_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startWordSelection starts a game and waits until the drawer is offered
+// words.
+func startWordSelection(t *testing.T) (*gametest.Simulator, gametest.Turn) {
+	t.Helper()
+	sim := newTestGame(t, 2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+	return sim, sim.Turn()
+}
+
+func TestSelectWordValidation(t *testing.T) {
+	sim, turn := startWordSelection(t)
+	guesser := guessersOf(sim)[0]
+	outOfRange := len(turn.SelectableWords)
+	second := 1
+
+	sim.Send(guesser, e.SelectWord, e.SelectWordPayload{WordID: turn.SelectableWords[0].Id})
+	assert.Equal(t, e.CodeNotYourTurn, lastError(t, sim, guesser).Code)
+
+	for _, pt := range []e.SelectWordPayload{
+		{},
+		{Index: &outOfRange},
+		{WordID: 9999},
+		{Word: &shared.Word{Word: "Banana"}},
+	} {
+		sim.Send(turn.DrawerID, e.SelectWord, pt)
+		assert.Equal(t, e.CodeWordNotOffered, lastError(t, sim, turn.DrawerID).Code)
+	}
+	assert.Nil(t, sim.Turn().Word)
+
+	sim.Send(turn.DrawerID, e.SelectWord, e.SelectWordPayload{Index: &second})
+	require.NotNil(t, sim.Turn().Word)
+	assert.Equal(t, turn.SelectableWords[1], *sim.Turn().Word)
+	sim.RunUntil(func() bool { return sim.Phase() == g.PhaseDrawing })
+}
+
+func TestSelectWordByLegacyWord(t *testing.T) {
+	sim, turn := startWordSelection(t)
+	offered := turn.SelectableWords[2]
+
+	sim.Send(turn.DrawerID, e.SelectWord, e.SelectWordPayload{Word: &shared.Word{Word: offered.Word}})
+	require.NotNil(t, sim.Turn().Word)
+	assert.Equal(t, offered, *sim.Turn().Word)
+}
+
+func TestRerollWords(t *testing.T) {
+	sim, turn := startWordSelection(t)
+	guesser := guessersOf(sim)[0]
+
+	sim.Send(guesser, e.RerollWords, nil)
+	assert.Equal(t, e.CodeNotYourTurn, lastError(t, sim, guesser).Code)
+
+	sim.Send(turn.DrawerID, e.RerollWords, nil)
+	msg, ok := sim.Messenger.Last(turn.DrawerID, e.OpenSelectWordModal)
+	require.True(t, ok)
+	var choices e.OpenSelectWordModalMessage
+	require.NoError(t, json.Unmarshal(msg.Payload, &choices))
+	assert.Equal(t, 0, choices.RerollsLeft)
+	require.NotEmpty(t, choices.SelectableWords)
+	assert.Equal(t, choices.SelectableWords, sim.Turn().SelectableWords)
+	for _, word := range choices.SelectableWords {
+		assert.NotContains(t, turn.SelectableWords, word)
+	}
+
+	sim.Send(turn.DrawerID, e.RerollWords, nil)
+	assert.Equal(t, e.CodeNoRerollsLeft, lastError(t, sim, turn.DrawerID).Code)
+
+	// Only the new words can be chosen.
+	sim.Send(turn.DrawerID, e.SelectWord, e.SelectWordPayload{WordID: turn.SelectableWords[0].Id})
+	assert.Equal(t, e.CodeWordNotOffered, lastError(t, sim, turn.DrawerID).Code)
+	sim.Send(turn.DrawerID, e.SelectWord, e.SelectWordPayload{WordID: choices.SelectableWords[0].Id})
+	require.NotNil(t, sim.Turn().Word)
+	assert.Equal(t, choices.SelectableWords[0], *sim.Turn().Word)
+}
+
+func TestOfferedWordsFollowDifficultyMix(t *testing.T) {
+	_, turn := startWordSelection(t)
+
+	var difficulties []string
+	for _, word := range turn.SelectableWords {
+		difficulties = append(difficulties, word.Difficulty)
+	}
+	assert.Equal(t, shared.Difficulties, difficulties)
+}
+
+func TestCategoriesOption(t *testing.T) {
+	options := gameOptions
+	options.Categories = []string{"Animals"}
+	sim := gametest.New(t, options)
+	sim.JoinN(2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+
+	// Animals has no hard word, so only its two words can be offered.
+	offered := sim.Turn().SelectableWords
+	assert.Len(t, offered, 2)
+	for _, word := range offered {
+		assert.Equal(t, "Animals", word.Category)
+	}
+}
+
+func TestUnknownCategoryRejected(t *testing.T) {
+	sim := newTestGame(t, 2)
+	options := gameOptions
+	options.Categories = []string{"Animals", "Vehicles"}
+
+	sim.Send(sim.Host(), e.UpdateOptions, e.UpdateOptionsPayload{Options: options})
+	err := lastError(t, sim, sim.Host())
+	assert.Equal(t, e.CodeInvalidOptions, err.Code)
+	assert.Contains(t, err.Message, "Vehicles")
+}
+
+func wordIDs(words []shared.Word) []uint64 {
+	ids := make([]uint64, len(words))
+	for i, w := range words {
+		ids[i] = w.Id
+	}
+	return ids
+}
+
+func TestWordsAreNotOfferedTwice(t *testing.T) {
+	sim, first := startWordSelection(t)
+	sim.Send(first.DrawerID, e.SelectWord, e.SelectWordPayload{WordID: first.SelectableWords[0].Id})
+	sim.RunUntil(func() bool {
+		turn := sim.Turn()
+		return turn.DrawerID != first.DrawerID && len(turn.SelectableWords) > 0
+	})
+
+	second := sim.Turn().SelectableWords
+	require.Len(t, second, 3)
+	for _, id := range wordIDs(second) {
+		assert.NotContains(t, wordIDs(first.SelectableWords), id)
+	}
+	assert.Len(t, sim.Game.UsedWords, len(gametest.DefaultWords))
+}
+
+func TestWordsRepeatOnceAllAreUsed(t *testing.T) {
+	sim, first := startWordSelection(t)
+	// The reroll uses up the rest of the word bank.
+	sim.Send(first.DrawerID, e.RerollWords, nil)
+	rerolled := sim.Turn().SelectableWords
+	sim.Send(first.DrawerID, e.SelectWord, e.SelectWordPayload{WordID: rerolled[0].Id})
+	sim.RunUntil(func() bool {
+		turn := sim.Turn()
+		return turn.DrawerID != first.DrawerID && len(turn.SelectableWords) > 0
+	})
+
+	second := wordIDs(sim.Turn().SelectableWords)
+	assert.Len(t, second, 3)
+	slices.Sort(second)
+	assert.Len(t, slices.Compact(second), 3, "words offered together must differ")
+}
+
+func TestRerollWithNoOtherWords(t *testing.T) {
+	options := gameOptions
+	options.Categories = []string{"Animals"}
+	sim := gametest.New(t, options)
+	sim.JoinN(2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return len(sim.Turn().SelectableWords) > 0 })
+	turn := sim.Turn()
+
+	// Both animals are already on offer, so the offer stands and the reroll
+	// is not used up.
+	for range 2 {
+		sim.Send(turn.DrawerID, e.RerollWords, nil)
+		assert.Equal(t, e.CodeNoWordsLeft, lastError(t, sim, turn.DrawerID).Code)
+		assert.Equal(t, turn.SelectableWords, sim.Turn().SelectableWords)
+	}
+}
+
+func TestTurnIsSkippedWithoutWords(t *testing.T) {
+	options := gameOptions
+	options.Categories = []string{"Nothing"}
+	sim := gametest.New(t, options)
+	sim.JoinN(2)
+	sim.StartGame()
+	sim.RunUntil(func() bool { return sim.Phase() != g.PhaseCountdown })
+
+	assert.Equal(t, g.PhaseTurnResults, sim.Phase())
+	assert.Empty(t, messagesOf(sim, e.OpenSelectWordModal))
+}
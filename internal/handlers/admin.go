@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ajstraight619/pictionary-server/internal/db"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/Ajstraight619/pictionary-server/internal/wordpack"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	defaultWordPageSize = 100
+	maxWordPageSize     = 1000
+	// maxPackSize bounds the size of an uploaded word pack, in bytes.
+	maxPackSize = 8 << 20
+)
+
+// AddWordRequest is a word added to the word bank outside of any pack.
+type AddWordRequest struct {
+	Word       string   `json:"word"`
+	Category   string   `json:"category"`
+	Difficulty string   `json:"difficulty,omitempty"`
+	Aliases    []string `json:"aliases,omitempty"`
+	Disabled   bool     `json:"disabled,omitempty"`
+}
+
+// SetDisabledRequest enables or disables a word or every word in a pack.
+type SetDisabledRequest struct {
+	Disabled *bool `json:"disabled"`
+}
+
+// AdminAuth requires requests to carry token as a bearer token.
+func AdminAuth(token string) echo.MiddlewareFunc {
+	return middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		Validator: func(key string, c echo.Context) (bool, error) {
+			return subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1, nil
+		},
+		ErrorHandler: func(err error, c echo.Context) error {
+			return c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized", Code: "unauthorized"})
+		},
+	})
+}
+
+// ListWordsHandler lists words in the word bank, disabled ones included. The
+// category, pack and search query parameters narrow the list, and limit and
+// offset page through it.
+func ListWordsHandler(c echo.Context) error {
+	query := db.WordQuery{
+		Category: c.QueryParam("category"),
+		Pack:     c.QueryParam("pack"),
+		Search:   c.QueryParam("search"),
+		Limit:    defaultWordPageSize,
+	}
+	if err := echo.QueryParamsBinder(c).
+		Int("limit", &query.Limit).
+		Int("offset", &query.Offset).
+		BindError(); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid limit or offset"})
+	}
+	query.Limit = min(max(query.Limit, 1), maxWordPageSize)
+
+	words, total, err := db.ListWords(query)
+	if err != nil {
+		log.Println("ListWordsHandler: error listing words:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list words"})
+	}
+	if words == nil {
+		words = []shared.Word{}
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"words": words, "total": total})
+}
+
+// AddWordHandler adds a single word to the word bank.
+func AddWordHandler(c echo.Context) error {
+	var req AddWordRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request"})
+	}
+	entry := wordpack.Entry{Word: req.Word, Category: req.Category, Difficulty: req.Difficulty, Aliases: req.Aliases}
+	if err := entry.Normalize(); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error(), Code: "invalidWord"})
+	}
+
+	word := shared.Word{
+		Word:       entry.Word,
+		Category:   entry.Category,
+		Difficulty: entry.Difficulty,
+		Aliases:    entry.Aliases,
+		Disabled:   req.Disabled,
+	}
+	if err := db.AddWord(&word); err != nil {
+		if errors.Is(err, db.ErrDuplicateWord) {
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error(), Code: "duplicateWord"})
+		}
+		log.Println("AddWordHandler: error adding word:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to add word"})
+	}
+	return c.JSON(http.StatusCreated, word)
+}
+
+// UpdateWordHandler enables or disables a word.
+func UpdateWordHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{Error: db.ErrWordNotFound.Error(), Code: "wordNotFound"})
+	}
+	var req SetDisabledRequest
+	if err := c.Bind(&req); err != nil || req.Disabled == nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request"})
+	}
+
+	word, err := db.SetWordDisabled(id, *req.Disabled)
+	if err != nil {
+		if errors.Is(err, db.ErrWordNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error(), Code: "wordNotFound"})
+		}
+		log.Println("UpdateWordHandler: error updating word:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update word"})
+	}
+	return c.JSON(http.StatusOK, word)
+}
+
+// DeleteWordHandler removes a word from the word bank.
+func DeleteWordHandler(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err == nil {
+		err = db.DeleteWord(id)
+	} else {
+		err = db.ErrWordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrWordNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error(), Code: "wordNotFound"})
+		}
+		log.Println("DeleteWordHandler: error deleting word:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete word"})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListPacksHandler lists the word packs with how many words each holds.
+func ListPacksHandler(c echo.Context) error {
+	packs, err := db.ListPacks()
+	if err != nil {
+		log.Println("ListPacksHandler: error listing packs:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list word packs"})
+	}
+	if packs == nil {
+		packs = []db.PackSummary{}
+	}
+	return c.JSON(http.StatusOK, packs)
+}
+
+// ImportPackHandler imports the word pack in the request body under the name
+// in the path, replacing any earlier import of it. The format query parameter
+// selects JSON or CSV; without it, a text/csv body is read as CSV and any
+// other as JSON. The version query parameter overrides the file's version.
+func ImportPackHandler(c echo.Context) error {
+	format, err := packFormat(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error(), Code: "invalidPack"})
+	}
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxPackSize)
+	pack, err := wordpack.Decode(body, format)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error(), Code: "invalidPack"})
+	}
+	pack.Name = c.Param("name")
+	if version := c.QueryParam("version"); version != "" {
+		pack.Version = version
+	}
+	if err := pack.Normalize(); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error(), Code: "invalidPack"})
+	}
+
+	result, err := db.ImportPack(pack)
+	if err != nil {
+		log.Println("ImportPackHandler: error importing pack:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to import word pack"})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// ExportPackHandler returns a word pack as a file, in the format named by the
+// format query parameter or as JSON.
+func ExportPackHandler(c echo.Context) error {
+	format := wordpack.FormatJSON
+	if name := c.QueryParam("format"); name != "" {
+		var err error
+		if format, err = wordpack.ParseFormat(name); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		}
+	}
+
+	pack, err := db.ExportPack(c.Param("name"))
+	if err != nil {
+		if errors.Is(err, db.ErrPackNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error(), Code: "packNotFound"})
+		}
+		log.Println("ExportPackHandler: error exporting pack:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to export word pack"})
+	}
+
+	contentType := echo.MIMEApplicationJSONCharsetUTF8
+	if format == wordpack.FormatCSV {
+		contentType = "text/csv; charset=UTF-8"
+	}
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", pack.Name+"."+string(format)))
+	c.Response().WriteHeader(http.StatusOK)
+	return wordpack.Encode(c.Response(), format, pack)
+}
+
+// UpdatePackHandler enables or disables every word in a pack.
+func UpdatePackHandler(c echo.Context) error {
+	var req SetDisabledRequest
+	if err := c.Bind(&req); err != nil || req.Disabled == nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request"})
+	}
+
+	changed, err := db.SetPackDisabled(c.Param("name"), *req.Disabled)
+	if err != nil {
+		if errors.Is(err, db.ErrPackNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error(), Code: "packNotFound"})
+		}
+		log.Println("UpdatePackHandler: error updating pack:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update word pack"})
+	}
+	return c.JSON(http.StatusOK, map[string]int{"changed": changed})
+}
+
+// DeletePackHandler removes a word pack and the words no other pack holds.
+func DeletePackHandler(c echo.Context) error {
+	removed, err := db.DeletePack(c.Param("name"))
+	if err != nil {
+		if errors.Is(err, db.ErrPackNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error(), Code: "packNotFound"})
+		}
+		log.Println("DeletePackHandler: error deleting pack:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete word pack"})
+	}
+	return c.JSON(http.StatusOK, map[string]int{"removed": removed})
+}
+
+// packFormat returns the format of an uploaded word pack.
+func packFormat(c echo.Context) (wordpack.Format, error) {
+	if name := c.QueryParam("format"); name != "" {
+		return wordpack.ParseFormat(name)
+	}
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "text/csv") {
+		return wordpack.FormatCSV, nil
+	}
+	return wordpack.FormatJSON, nil
+}
@@ -107,11 +107,12 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 
-	"github.com/Ajstraight619/pictionary-server/internal/game"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
 	"github.com/Ajstraight619/pictionary-server/internal/server"
+	"github.com/Ajstraight619/pictionary-server/internal/session"
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -120,6 +121,9 @@ import (
 type CreateGameRequest struct {
 	Username string             `json:"username"`
 	Options  shared.GameOptions `json:"options"`
+	// WordList is a custom word list pasted by the host, as JSON or one word
+	// per line. It replaces Options.CustomWords.
+	WordList string `json:"wordList,omitempty"`
 }
 
 type JoinGameRequest struct {
@@ -127,18 +131,7 @@ type JoinGameRequest struct {
 	GameID   string `json:"gameID"`
 }
 
-var numPlayers = 2
-
-func CreateTestPlayers(numPlayers int, game *game.Game) []*shared.Player {
-	players := make([]*shared.Player, numPlayers)
-
-	for i := 0; i < numPlayers; i++ {
-		players[i] = game.NewPlayer(uuid.New().String(), fmt.Sprintf("player %d", i), false)
-	}
-	return players
-}
-
-func CreateGameHandler(c echo.Context, server *server.GameServer) error {
+func CreateGameHandler(c echo.Context, server *server.GameServer, sessions *session.Signer) error {
 	var req CreateGameRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
@@ -147,6 +140,33 @@ func CreateGameHandler(c echo.Context, server *server.GameServer) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Username is required"})
 	}
 
+	if req.WordList != "" {
+		words, err := shared.ParseWordList(req.WordList)
+		if err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:  "Invalid game options",
+				Code:   "invalidOptions",
+				Fields: map[string]string{"wordList": err.Error()},
+			})
+		}
+		req.Options.CustomWords = words
+	}
+
+	req.Options.ApplyDefaults()
+	if err := server.ValidateOptions(req.Options); err != nil {
+		var optionsErr *shared.OptionsError
+		if errors.As(err, &optionsErr) {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:  "Invalid game options",
+				Code:   "invalidOptions",
+				Fields: optionsErr.Fields,
+			})
+		}
+		// Anything else, such as the category lookup failing, is not the
+		// request's fault.
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to validate game options"})
+	}
+
 	playerID := uuid.New().String()
 	gameID := uuid.New().String()
 
@@ -160,20 +180,23 @@ func CreateGameHandler(c echo.Context, server *server.GameServer) error {
 
 	// Add the host player
 	player := game.NewPlayer(playerID, req.Username, true)
-	game.AddPlayer(player)
+	player.IP = c.RealIP()
 	player.Pending = true
+	game.AddPlayer(player)
 
-	for _, p := range CreateTestPlayers(numPlayers, game) {
-		game.AddPlayer(p)
+	token, err := sessions.Issue(gameID, playerID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create session"})
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"gameID":   gameID,
 		"playerID": playerID,
+		"token":    token,
 	})
 }
 
-func JoinGameHandler(c echo.Context, server *server.GameServer) error {
+func JoinGameHandler(c echo.Context, server *server.GameServer, sessions *session.Signer) error {
 	var req JoinGameRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
@@ -184,13 +207,29 @@ func JoinGameHandler(c echo.Context, server *server.GameServer) error {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "Game not found"})
 	}
 
+	if game.IsBanned("", c.RealIP()) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "You are banned from this game"})
+	}
+
 	playerID := uuid.New().String()
 	player := game.NewPlayer(playerID, req.Username, false)
+	player.IP = c.RealIP()
 	player.Pending = true
-	game.AddPlayer(player)
+	if err := game.AddPlayer(player); err != nil {
+		if errors.Is(err, g.ErrGameFull) {
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "Game is full", Code: "gameFull"})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to join game"})
+	}
+
+	token, err := sessions.Issue(req.GameID, playerID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create session"})
+	}
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"gameID":   req.GameID,
 		"playerID": playerID,
+		"token":    token,
 	})
 }
@@ -0,0 +1,32 @@
+package handlers_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/Ajstraight619/pictionary-server/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateGameOptionErrors(t *testing.T) {
+	srv := newTestServer(t)
+	create := func(body string) int {
+		t.Helper()
+		resp, err := http.Post(srv.URL+"/game/create", "application/json", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnprocessableEntity, create(`{"username":"host","options":{"roundLimit":99}}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, create(`{"username":"host","options":{"categories":["Vehicles"]}}`))
+
+	// With the database gone the categories cannot be checked, which is the
+	// server's fault rather than the request's.
+	sqlDB, err := db.DB.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	assert.Equal(t, http.StatusInternalServerError, create(`{"username":"host","options":{"categories":["Animals"]}}`))
+}
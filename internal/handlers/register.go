@@ -34,23 +34,47 @@
 package handlers
 
 import (
+	"log"
+
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+	"github.com/Ajstraight619/pictionary-server/internal/config"
 	"github.com/Ajstraight619/pictionary-server/internal/server"
+	"github.com/Ajstraight619/pictionary-server/internal/session"
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterRoutes(e *echo.Echo, server *server.GameServer) {
+func RegisterRoutes(e *echo.Echo, server *server.GameServer, cfg *config.Config) {
+	sessions := session.NewSigner(cfg.SessionSecret, clock.Real())
+
 	e.POST("/game/create", func(c echo.Context) error {
-		return CreateGameHandler(c, server)
+		return CreateGameHandler(c, server, sessions)
 	})
 
 	e.POST("/game/join", func(c echo.Context) error {
-		return JoinGameHandler(c, server)
+		return JoinGameHandler(c, server, sessions)
 	})
 
 	e.GET("/game/:id", func(c echo.Context) error {
-		return ServeWs(c, server)
+		return ServeWs(c, server, sessions)
 	})
 
+	e.GET("/categories", CategoriesHandler)
+
+	if cfg.AdminToken != "" {
+		admin := e.Group("/admin", AdminAuth(cfg.AdminToken))
+		admin.GET("/words", ListWordsHandler)
+		admin.POST("/words", AddWordHandler)
+		admin.PATCH("/words/:id", UpdateWordHandler)
+		admin.DELETE("/words/:id", DeleteWordHandler)
+		admin.GET("/packs", ListPacksHandler)
+		admin.PUT("/packs/:name", ImportPackHandler)
+		admin.GET("/packs/:name", ExportPackHandler)
+		admin.PATCH("/packs/:name", UpdatePackHandler)
+		admin.DELETE("/packs/:name", DeletePackHandler)
+	} else {
+		log.Println("ADMIN_TOKEN is not set, the admin API is disabled")
+	}
+
 	// e.GET("/game/state/:id", func(c echo.Context) error {
 	// 	return CreateGameStateHandler(c, server)
 	// })
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"time"
 
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/protocol"
 	"github.com/Ajstraight619/pictionary-server/internal/server"
+	"github.com/Ajstraight619/pictionary-server/internal/session"
 	"github.com/Ajstraight619/pictionary-server/internal/utils"
 	"github.com/Ajstraight619/pictionary-server/internal/ws"
 	"github.com/gorilla/websocket"
@@ -22,9 +27,13 @@ var upgrader = websocket.Upgrader{
 
 type ErrorResponse struct {
 	Error string `json:"error"`
+	// Code is a stable identifier clients can match on.
+	Code string `json:"code,omitempty"`
+	// Fields maps each invalid request field to what is wrong with it.
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
-func ServeWs(c echo.Context, server *server.GameServer) error {
+func ServeWs(c echo.Context, server *server.GameServer, sessions *session.Signer) error {
 	gameID := c.Param("id")
 	log.Printf("ServeWs: received gameID: %s", gameID)
 
@@ -38,42 +47,72 @@ func ServeWs(c echo.Context, server *server.GameServer) error {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "Hub not found"})
 	}
 
-	playerID := c.QueryParam("playerID")
-	username := c.QueryParam("username")
+	// Browsers cannot set headers on a WebSocket handshake, so the session
+	// token travels in the query string.
+	claims, err := sessions.Verify(c.QueryParam("token"), gameID)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid session"})
+	}
+	playerID := claims.PlayerID
+
+	if game.IsBanned(playerID, c.RealIP()) {
+		return c.JSON(http.StatusForbidden, ErrorResponse{Error: "You are banned from this game"})
+	}
 
-	if playerID == "" || username == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "PlayerID and Username are required"})
+	if err := game.CheckSeat(playerID); err != nil {
+		if errors.Is(err, g.ErrGameFull) {
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "Game is full", Code: "gameFull"})
+		}
+		return c.JSON(http.StatusNotFound, ErrorResponse{Error: "Player not found"})
 	}
 
-	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	version, fromSubprotocol, err := protocol.Negotiate(websocket.Subprotocols(c.Request()), c.QueryParam(protocol.QueryParam))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to upgrade connection"})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported protocol version", Code: "unsupportedProtocol"})
+	}
+	var responseHeader http.Header
+	if fromSubprotocol {
+		responseHeader = http.Header{"Sec-Websocket-Protocol": {version.Subprotocol()}}
 	}
 
-	// Update the player's connection status in the game state.
-	player := game.GetPlayerByID(playerID)
-	if player == nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "Player not found"})
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), responseHeader)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Unable to upgrade connection"})
 	}
-	player.Pending = false
-	player.Connected = true
-	player.Client = ws.NewClient(hub, conn, playerID)
 
-	if wsClient, ok := player.Client.(*ws.Client); ok {
-		hub.Register <- wsClient
-	} else {
-		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
+	client := ws.NewClient(hub, conn, playerID, version)
+	player, reconnected, err := game.ConnectPlayer(playerID, c.RealIP(), client)
+	if err != nil {
+		// The seat expired, or the game filled up, between the check above
+		// and the upgrade.
+		if errors.Is(err, g.ErrGameFull) {
+			client.CloseWithReason(e.CloseGameFull, "Game is full")
+			go client.Write()
+		} else {
+			conn.Close()
+		}
+		return nil
+	}
+	// The welcome message goes first so the client knows the version before
+	// anything else arrives. Older versions drop it.
+	welcome, err := utils.CreateMessage(e.Welcome, e.WelcomeMessage{
+		ProtocolVersion: int(version),
+		Capabilities:    version.Capabilities(),
+	})
+	if err == nil {
+		client.Send <- welcome
 	}
+	hub.Register <- client
 
-	go player.Client.Write()
-	go player.Client.Read()
+	go client.Write()
+	go client.Read()
 
-	msgType := "playerJoined"
-	payload := map[string]interface{}{
-		"player": player,
+	msgType := e.PlayerJoined
+	if reconnected {
+		msgType = e.PlayerReconnected
 	}
 
-	b, err := utils.CreateMessage(msgType, payload)
+	b, err := utils.CreateMessage(msgType, e.PlayerJoinedMessage{Player: player})
 
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Internal server error"})
@@ -81,10 +120,12 @@ func ServeWs(c echo.Context, server *server.GameServer) error {
 
 	hub.Broadcast <- b
 
-	// Temporary fix to make sure the game state the ws connection is
+	// Temporary fix to make sure the ws connection is registered before the
+	// resync is sent.
 	time.AfterFunc(200*time.Millisecond, func() {
 		log.Println("game state:", game)
 		game.BroadcastGameState()
+		game.SendCanvasSnapshot(playerID)
 	})
 
 	return nil
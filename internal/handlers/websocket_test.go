@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/handlers"
+	"github.com/Ajstraight619/pictionary-server/internal/server"
+	"github.com/Ajstraight619/pictionary-server/internal/session"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+type seat struct {
+	GameID   string `json:"gameID"`
+	PlayerID string `json:"playerID"`
+	Token    string `json:"token"`
+}
+
+// newTestServer serves the game routes backed by a fresh game server.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	require.NoError(t, gametest.UseMemoryDB(gametest.DefaultWords))
+
+	games := server.NewGameServer(nil)
+	sessions := session.NewSigner("secret", clock.Real())
+	router := echo.New()
+	router.POST("/game/create", func(c echo.Context) error {
+		return handlers.CreateGameHandler(c, games, sessions)
+	})
+	router.POST("/game/join", func(c echo.Context) error {
+		return handlers.JoinGameHandler(c, games, sessions)
+	})
+	router.GET("/game/:id", func(c echo.Context) error {
+		return handlers.ServeWs(c, games, sessions)
+	})
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(func() {
+		srv.Close()
+		// Shutdown waits out its context before returning, and there is
+		// nothing here to wait for.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		games.Shutdown(ctx)
+	})
+	return srv
+}
+
+func post(t *testing.T, srv *httptest.Server, path string, body any) seat {
+	t.Helper()
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+	resp, err := http.Post(srv.URL+path, "application/json", bytes.NewReader(b))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var s seat
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&s))
+	return s
+}
+
+func dial(srv *httptest.Server, s seat) (*websocket.Conn, *http.Response, error) {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/game/" + s.GameID + "?token=" + s.Token
+	return websocket.DefaultDialer.Dial(url, nil)
+}
+
+func TestServeWsRefusesBannedPlayers(t *testing.T) {
+	srv := newTestServer(t)
+	host := post(t, srv, "/game/create", map[string]any{"username": "host"})
+	guest := post(t, srv, "/game/join", map[string]any{"username": "guest", "gameID": host.GameID})
+
+	hostConn, _, err := dial(srv, host)
+	require.NoError(t, err)
+	defer hostConn.Close()
+	guestConn, _, err := dial(srv, guest)
+	require.NoError(t, err)
+	defer guestConn.Close()
+
+	ban, err := json.Marshal(map[string]any{
+		"type":    e.BanPlayer,
+		"payload": e.KickPlayerPayload{PlayerID: guest.PlayerID, Reason: "cheating"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, hostConn.WriteMessage(websocket.TextMessage, ban))
+
+	// The guest is sent a close frame saying why.
+	guestConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err = guestConn.ReadMessage()
+		if err != nil {
+			break
+		}
+	}
+	var closeErr *websocket.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, e.CloseBanned, closeErr.Code)
+	assert.Equal(t, "cheating", closeErr.Text)
+
+	// Their session no longer gets them back in.
+	_, resp, err := dial(srv, guest)
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Ajstraight619/pictionary-server/internal/db"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/labstack/echo/v4"
+)
+
+// CategoriesHandler lists the word categories a game can be limited to, with
+// how many words each has at every difficulty.
+func CategoriesHandler(c echo.Context) error {
+	categories, err := db.GetCategories()
+	if err != nil {
+		log.Println("CategoriesHandler: error loading categories:", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to load categories"})
+	}
+	if categories == nil {
+		categories = []shared.WordCategory{}
+	}
+	return c.JSON(http.StatusOK, categories)
+}
@@ -0,0 +1,167 @@
+// Package messagingtest provides an in-memory messaging.Messenger for tests.
+package messagingtest
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/messaging"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+)
+
+// Message is a single recorded outgoing message. To is empty for broadcasts.
+type Message struct {
+	To      string
+	Type    string
+	Payload json.RawMessage
+	Raw     []byte
+}
+
+// Recorder captures every broadcast and per-player send, and lets tests feed
+// events and disconnects into the game loop as if they came from the hub.
+type Recorder struct {
+	mu          sync.Mutex
+	messages    []Message
+	events      chan e.GameEvent
+	disconnects chan messaging.Disconnect
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		events:      make(chan e.GameEvent, 64),
+		disconnects: make(chan messaging.Disconnect, 16),
+	}
+}
+
+func (r *Recorder) BroadcastMessage(message []byte) {
+	r.record("", message)
+}
+
+func (r *Recorder) SendToPlayer(playerID string, message []byte) {
+	r.record(playerID, message)
+}
+
+func (r *Recorder) GameEventChannel() <-chan e.GameEvent {
+	return r.events
+}
+
+func (r *Recorder) DisconnectChannel() <-chan messaging.Disconnect {
+	return r.disconnects
+}
+
+// Inject delivers an event to the game as if playerID had sent it.
+func (r *Recorder) Inject(playerID, eventType string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	r.events <- e.GameEvent{Type: eventType, Payload: b, PlayerID: playerID}
+	return nil
+}
+
+// InjectJSON delivers a raw client message to the game as if playerID's
+// connection had received it. Like a real connection, it stamps playerID on
+// the event.
+func (r *Recorder) InjectJSON(playerID string, message []byte) error {
+	var event e.GameEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return err
+	}
+	event.PlayerID = playerID
+	r.events <- event
+	return nil
+}
+
+// Disconnect reports client, playerID's connection, as closed.
+func (r *Recorder) Disconnect(playerID string, client shared.ClientInterface) {
+	r.disconnects <- messaging.Disconnect{PlayerID: playerID, Client: client}
+}
+
+// Pending returns how many injected events and disconnects the game has not
+// received yet.
+func (r *Recorder) Pending() int {
+	return len(r.events) + len(r.disconnects)
+}
+
+// Messages returns a copy of everything recorded so far.
+func (r *Recorder) Messages() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.messages)
+}
+
+// Len returns the number of recorded messages.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.messages)
+}
+
+// ReceivedBy returns the messages playerID would have received: every
+// broadcast plus the messages sent to them directly.
+func (r *Recorder) ReceivedBy(playerID string) []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var received []Message
+	for _, msg := range r.messages {
+		if msg.To == "" || msg.To == playerID {
+			received = append(received, msg)
+		}
+	}
+	return received
+}
+
+// Last returns the most recent message of the given type received by
+// playerID, or false if there is none.
+func (r *Recorder) Last(playerID, msgType string) (Message, bool) {
+	received := r.ReceivedBy(playerID)
+	for i := len(received) - 1; i >= 0; i-- {
+		if received[i].Type == msgType {
+			return received[i], true
+		}
+	}
+	return Message{}, false
+}
+
+// Reset forgets every recorded message.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = nil
+}
+
+// Types lists the types of msgs, keeping only those in include when it is
+// non-empty, and collapsing consecutive repeats such as timer ticks.
+func Types(msgs []Message, include ...string) []string {
+	var types []string
+	for _, msg := range msgs {
+		if len(include) > 0 && !slices.Contains(include, msg.Type) {
+			continue
+		}
+		if n := len(types); n > 0 && types[n-1] == msg.Type {
+			continue
+		}
+		types = append(types, msg.Type)
+	}
+	return types
+}
+
+func (r *Recorder) record(to string, raw []byte) {
+	var envelope struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	// Malformed messages are still recorded so tests can catch them.
+	_ = json.Unmarshal(raw, &envelope)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, Message{
+		To:      to,
+		Type:    envelope.Type,
+		Payload: envelope.Payload,
+		Raw:     slices.Clone(raw),
+	})
+}
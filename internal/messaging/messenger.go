@@ -1,9 +1,22 @@
 package messaging
 
-import e "github.com/Ajstraight619/pictionary-server/internal/events"
+import (
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+)
 
 type Messenger interface {
 	BroadcastMessage(message []byte)
 	SendToPlayer(playerID string, message []byte)
 	GameEventChannel() <-chan e.GameEvent
+	// DisconnectChannel delivers each player whose last connection closed.
+	DisconnectChannel() <-chan Disconnect
+}
+
+// Disconnect reports that a player's connection closed.
+type Disconnect struct {
+	PlayerID string
+	// Client is the connection that closed. The player may already have
+	// connected again with another one.
+	Client shared.ClientInterface
 }
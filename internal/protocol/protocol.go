@@ -0,0 +1,216 @@
+// Package protocol negotiates the version of the WebSocket protocol spoken on
+// a connection and rewrites outgoing messages for clients that speak an older
+// version than the server.
+//
+// The game always encodes messages for the current version. A connection on
+// an older version passes every message through Downgrade before writing it.
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+)
+
+// Version is a revision of the WebSocket protocol.
+type Version int
+
+const (
+	// V1 is the protocol spoken before versions were negotiated. Some of its
+	// payloads are bare values rather than objects, and it has no acks.
+	V1 Version = 1
+	// V2 wraps every payload in an object and acknowledges events that carry
+	// a request ID.
+	V2 Version = 2
+
+	// Current is the version the game encodes messages for.
+	Current = V2
+)
+
+// QueryParam is the query string parameter clients that cannot set a
+// subprotocol use to ask for a version, as in ?v=2.
+const QueryParam = "v"
+
+const subprotocolPrefix = "pictionary.v"
+
+// Optional features announced in the welcome message.
+const (
+	CapabilityAcks          = "acks"
+	CapabilityObjectPayload = "objectPayloads"
+)
+
+var ErrUnsupportedVersion = errors.New("unsupported protocol version")
+
+// Supported lists the versions the server speaks, newest first.
+var Supported = []Version{V2, V1}
+
+// Subprotocol is the WebSocket subprotocol name for v, such as
+// "pictionary.v2".
+func (v Version) Subprotocol() string {
+	return subprotocolPrefix + strconv.Itoa(int(v))
+}
+
+func (v Version) supported() bool {
+	for _, s := range Supported {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Capabilities lists the optional features the server uses on a connection
+// speaking v.
+func (v Version) Capabilities() []string {
+	if v < V2 {
+		return []string{}
+	}
+	return []string{CapabilityAcks, CapabilityObjectPayload}
+}
+
+// Negotiate picks the version for a new connection. The newest supported
+// subprotocol the client offered wins. Failing that the query parameter is
+// used, and a client that asks for nothing gets V1. fromSubprotocol reports
+// whether the choice must be confirmed in the handshake response.
+func Negotiate(subprotocols []string, query string) (v Version, fromSubprotocol bool, err error) {
+	best := Version(0)
+	for _, name := range subprotocols {
+		n, ok := strings.CutPrefix(name, subprotocolPrefix)
+		if !ok {
+			continue
+		}
+		offered, err := strconv.Atoi(n)
+		if err != nil {
+			continue
+		}
+		if v := Version(offered); v.supported() && v > best {
+			best = v
+		}
+	}
+	if best != 0 {
+		return best, true, nil
+	}
+
+	if query == "" {
+		return V1, false, nil
+	}
+	n, err := strconv.Atoi(query)
+	if err != nil || !Version(n).supported() {
+		return 0, false, fmt.Errorf("%w: %q", ErrUnsupportedVersion, query)
+	}
+	return Version(n), false, nil
+}
+
+// downgrader rewrites the payload of one message type for an older version.
+// ok is false when the message should not be sent at all.
+type downgrader func(payload json.RawMessage) (out any, ok bool)
+
+// downgrades holds, for each version older than Current, how to rewrite the
+// messages that changed since. Messages without an entry pass through as is.
+var downgrades = map[Version]map[string]downgrader{
+	V1: {
+		e.DrawingPlayerChanged: unwrap("player"),
+		e.RevealedLetter:       unwrap("revealedLetters"),
+		e.GameEnded:            addField("type", e.GameEnded),
+		e.GameState:            gameStateV1,
+		e.Ack:                  drop,
+		e.Welcome:              drop,
+	},
+}
+
+// Downgrade rewrites a message encoded for Current so a client speaking v
+// understands it. ok is false when the message has no equivalent in v and
+// should be dropped.
+func Downgrade(v Version, message []byte) (out []byte, ok bool) {
+	rules := downgrades[v]
+	if len(rules) == 0 {
+		return message, true
+	}
+	var envelope struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return message, true
+	}
+	rule, exists := rules[envelope.Type]
+	if !exists {
+		return message, true
+	}
+	payload, ok := rule(envelope.Payload)
+	if !ok {
+		return nil, false
+	}
+	out, err := json.Marshal(map[string]any{
+		"type":    envelope.Type,
+		"payload": payload,
+	})
+	if err != nil {
+		return message, true
+	}
+	return out, true
+}
+
+// unwrap sends the value of a single field as the whole payload.
+func unwrap(field string) downgrader {
+	return func(payload json.RawMessage) (any, bool) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return payload, true
+		}
+		return fields[field], true
+	}
+}
+
+// addField adds a field the older version expects in the payload.
+func addField(field string, value any) downgrader {
+	return func(payload json.RawMessage) (any, bool) {
+		var fields map[string]any
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return payload, true
+		}
+		fields[field] = value
+		return fields, true
+	}
+}
+
+// Version 1 described the stage of a turn with an integer turn.phase instead
+// of the game-wide phase.
+const (
+	v1PhaseWordSelection = 0
+	v1PhaseDrawing       = 1
+)
+
+// gameStateV1 replaces the phase and its deadline with turn.phase. Turns stay
+// in the drawing phase while their results are shown, as they did in version
+// 1.
+func gameStateV1(payload json.RawMessage) (any, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload, true
+	}
+	var phase e.GamePhase
+	json.Unmarshal(fields["phase"], &phase)
+	delete(fields, "phase")
+	delete(fields, "phaseDeadline")
+
+	var turn map[string]json.RawMessage
+	if err := json.Unmarshal(fields["turn"], &turn); err != nil || turn == nil {
+		return fields, true
+	}
+	turnPhase := v1PhaseWordSelection
+	if phase == "drawing" || phase == "turnResults" {
+		turnPhase = v1PhaseDrawing
+	}
+	turn["phase"], _ = json.Marshal(turnPhase)
+	fields["turn"], _ = json.Marshal(turn)
+	return fields, true
+}
+
+func drop(json.RawMessage) (any, bool) {
+	return nil, false
+}
@@ -0,0 +1,130 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/protocol"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/Ajstraight619/pictionary-server/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name            string
+		subprotocols    []string
+		query           string
+		want            protocol.Version
+		fromSubprotocol bool
+		wantErr         bool
+	}{
+		{name: "nothing requested", want: protocol.V1},
+		{name: "newest subprotocol", subprotocols: []string{"pictionary.v1", "pictionary.v2"}, want: protocol.V2, fromSubprotocol: true},
+		{name: "unknown subprotocols ignored", subprotocols: []string{"chat", "pictionary.v9"}, query: "2", want: protocol.V2},
+		{name: "subprotocol beats query", subprotocols: []string{"pictionary.v1"}, query: "2", want: protocol.V1, fromSubprotocol: true},
+		{name: "query", query: "1", want: protocol.V1},
+		{name: "unsupported query", query: "9", wantErr: true},
+		{name: "malformed query", query: "two", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fromSubprotocol, err := protocol.Negotiate(tt.subprotocols, tt.query)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, protocol.ErrUnsupportedVersion)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.fromSubprotocol, fromSubprotocol)
+		})
+	}
+}
+
+func message(t *testing.T, msgType string, payload any) []byte {
+	t.Helper()
+	b, err := utils.CreateMessage(msgType, payload)
+	require.NoError(t, err)
+	return b
+}
+
+func TestDowngradeV1(t *testing.T) {
+	player := &shared.Player{ID: "p1", Username: "alice"}
+
+	out, ok := protocol.Downgrade(protocol.V1, message(t, e.DrawingPlayerChanged, e.DrawingPlayerChangedMessage{Player: player}))
+	require.True(t, ok)
+	assert.Equal(t, string(message(t, e.DrawingPlayerChanged, player)), string(out))
+
+	out, ok = protocol.Downgrade(protocol.V1, message(t, e.RevealedLetter, e.RevealedLetterMessage{RevealedLetters: []rune("c_t")}))
+	require.True(t, ok)
+	assert.JSONEq(t, `{"type":"revealedLetter","payload":[99,95,116]}`, string(out))
+
+	out, ok = protocol.Downgrade(protocol.V1, message(t, e.GameEnded, e.GameEndedMessage{Message: "bye"}))
+	require.True(t, ok)
+	assert.JSONEq(t, `{"type":"gameEnded","payload":{"type":"gameEnded","message":"bye"}}`, string(out))
+
+	_, ok = protocol.Downgrade(protocol.V1, message(t, e.Ack, e.AckPayload{Event: e.Chat, RequestID: "1"}))
+	assert.False(t, ok)
+	_, ok = protocol.Downgrade(protocol.V1, message(t, e.Welcome, e.WelcomeMessage{ProtocolVersion: 1}))
+	assert.False(t, ok)
+
+	timer := message(t, e.TurnTimer, e.TimerMessage{TimeRemaining: 5})
+	out, ok = protocol.Downgrade(protocol.V1, timer)
+	require.True(t, ok)
+	assert.Equal(t, timer, out)
+}
+
+func TestDowngradeGameStateV1(t *testing.T) {
+	deadline := time.Unix(60, 0).UTC()
+	for phase, turnPhase := range map[e.GamePhase]int{
+		"lobby":         0,
+		"wordSelection": 0,
+		"drawing":       1,
+		"turnResults":   1,
+	} {
+		state := e.GameStateMessage{
+			ID:            "game",
+			Phase:         phase,
+			PhaseDeadline: &deadline,
+			Turn:          e.TurnState{CurrentDrawerID: "p1"},
+		}
+		out, ok := protocol.Downgrade(protocol.V1, message(t, e.GameState, state))
+		require.True(t, ok)
+
+		var msg struct {
+			Payload map[string]json.RawMessage `json:"payload"`
+		}
+		require.NoError(t, json.Unmarshal(out, &msg))
+		assert.NotContains(t, msg.Payload, "phase")
+		assert.NotContains(t, msg.Payload, "phaseDeadline")
+		assert.JSONEq(t, `"game"`, string(msg.Payload["id"]))
+
+		var turn struct {
+			CurrentDrawerID string `json:"currentDrawerID"`
+			Phase           int    `json:"phase"`
+		}
+		require.NoError(t, json.Unmarshal(msg.Payload["turn"], &turn), phase)
+		assert.Equal(t, "p1", turn.CurrentDrawerID)
+		assert.Equal(t, turnPhase, turn.Phase, phase)
+	}
+}
+
+func TestDowngradeCurrent(t *testing.T) {
+	for _, msg := range [][]byte{
+		message(t, e.DrawingPlayerChanged, e.DrawingPlayerChangedMessage{}),
+		message(t, e.Ack, e.AckPayload{Event: e.Chat}),
+	} {
+		out, ok := protocol.Downgrade(protocol.Current, msg)
+		assert.True(t, ok)
+		assert.Equal(t, msg, out)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	assert.Empty(t, protocol.V1.Capabilities())
+	assert.Contains(t, protocol.V2.Capabilities(), protocol.CapabilityAcks)
+	assert.Equal(t, "pictionary.v2", protocol.V2.Subprotocol())
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Ajstraight619/pictionary-server/internal/chat"
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
 	"github.com/Ajstraight619/pictionary-server/internal/game"
 	"github.com/Ajstraight619/pictionary-server/internal/shared"
 	"github.com/Ajstraight619/pictionary-server/internal/ws"
@@ -22,17 +24,27 @@ type GameServer struct {
 	cancelFunc context.CancelFunc
 	games      map[string]*GameInstance // Change from *game.Games to map of GameInstance
 	mu         sync.RWMutex             // Add mutex for thread safety
+	blocklist  *chat.Blocklist
 }
 
-func NewGameServer() *GameServer {
+// NewGameServer creates a server whose games mask the words in blocklist in
+// chat. blocklist may be nil.
+func NewGameServer(blocklist *chat.Blocklist) *GameServer {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &GameServer{
 		ctx:        ctx,
 		cancelFunc: cancel,
 		games:      make(map[string]*GameInstance),
+		blocklist:  blocklist,
 	}
 }
 
+// ValidateOptions checks options for a new game, including its custom words
+// against the server's blocklist.
+func (s *GameServer) ValidateOptions(options shared.GameOptions) error {
+	return game.ValidateOptions(options, s.blocklist)
+}
+
 // CreateGame now creates a game-specific context
 func (s *GameServer) CreateGame(id string, options shared.GameOptions) error {
 	s.mu.Lock()
@@ -42,8 +54,9 @@ func (s *GameServer) CreateGame(id string, options shared.GameOptions) error {
 
 	// Create hub and game with game-specific context
 	hub := ws.NewHub(gameCtx)
-	game := game.NewGame(gameCtx, id, options, hub, s)
+	game := game.NewGame(gameCtx, id, options, hub, s, clock.Real())
 	game.InitGameEvents()
+	game.SetBlocklist(s.blocklist)
 
 	s.games[id] = &GameInstance{
 		Game:       game,
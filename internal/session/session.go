@@ -0,0 +1,88 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+)
+
+// MaxAge bounds how long a token stays valid. Games live in memory, so a
+// token never needs to outlive a long session.
+const MaxAge = 24 * time.Hour
+
+var (
+	ErrInvalidToken = errors.New("invalid session token")
+	ErrExpiredToken = errors.New("session token expired")
+)
+
+// Claims identify a player's seat in a game.
+type Claims struct {
+	GameID   string `json:"gameID"`
+	PlayerID string `json:"playerID"`
+	IssuedAt int64  `json:"iat"`
+}
+
+// Signer issues and verifies HMAC-signed session tokens.
+type Signer struct {
+	secret []byte
+	clock  clock.Clock
+}
+
+func NewSigner(secret string, clk clock.Clock) *Signer {
+	return &Signer{secret: []byte(secret), clock: clk}
+}
+
+// Issue returns a token of the form base64(claims).base64(signature).
+func (s *Signer) Issue(gameID, playerID string) (string, error) {
+	claims := Claims{
+		GameID:   gameID,
+		PlayerID: playerID,
+		IssuedAt: s.clock.Now().Unix(),
+	}
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + s.sign(payload), nil
+}
+
+// Verify checks the token's signature and age and that it was issued for
+// gameID.
+func (s *Signer) Verify(token, gameID string) (*Claims, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return nil, ErrInvalidToken
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.GameID != gameID || claims.PlayerID == "" {
+		return nil, ErrInvalidToken
+	}
+	if s.clock.Now().Sub(time.Unix(claims.IssuedAt, 0)) > MaxAge {
+		return nil, ErrExpiredToken
+	}
+	return &claims, nil
+}
+
+func (s *Signer) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package session_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+	"github.com/Ajstraight619/pictionary-server/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(token string) string
+		secret  string
+		gameID  string
+		age     time.Duration
+		wantErr error
+	}{
+		{name: "valid"},
+		{name: "at max age", age: session.MaxAge},
+		{name: "expired", age: session.MaxAge + time.Second, wantErr: session.ErrExpiredToken},
+		{name: "tampered signature", tamper: flipSignature, wantErr: session.ErrInvalidToken},
+		{name: "tampered claims", tamper: swapClaims, wantErr: session.ErrInvalidToken},
+		{name: "no signature", tamper: stripSignature, wantErr: session.ErrInvalidToken},
+		{name: "other secret", secret: "other", wantErr: session.ErrInvalidToken},
+		{name: "other game", gameID: "game-2", wantErr: session.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewFake(time.Unix(1_700_000_000, 0))
+			token, err := session.NewSigner("secret", clk).Issue("game-1", "player-1")
+			require.NoError(t, err)
+			if tt.tamper != nil {
+				token = tt.tamper(token)
+			}
+			secret, gameID := "secret", "game-1"
+			if tt.secret != "" {
+				secret = tt.secret
+			}
+			if tt.gameID != "" {
+				gameID = tt.gameID
+			}
+			clk.Advance(tt.age)
+
+			claims, err := session.NewSigner(secret, clk).Verify(token, gameID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, claims)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "game-1", claims.GameID)
+			assert.Equal(t, "player-1", claims.PlayerID)
+		})
+	}
+}
+
+func flipSignature(token string) string {
+	b := []byte(token)
+	last := len(b) - 1
+	if b[last] == 'A' {
+		b[last] = 'B'
+	} else {
+		b[last] = 'A'
+	}
+	return string(b)
+}
+
+// swapClaims keeps the signature but replaces the claims with ones signed
+// for another player.
+func swapClaims(token string) string {
+	other, _ := session.NewSigner("secret", clock.NewFake(time.Unix(1_700_000_000, 0))).Issue("game-1", "player-2")
+	claims, _, _ := strings.Cut(other, ".")
+	_, sig, _ := strings.Cut(token, ".")
+	return claims + "." + sig
+}
+
+func stripSignature(token string) string {
+	claims, _, _ := strings.Cut(token, ".")
+	return claims
+}
@@ -0,0 +1,242 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type optionBounds struct {
+	def, min, max int
+}
+
+var (
+	turnTimeLimitBounds        = optionBounds{def: 60, min: 10, max: 300}
+	wordSelectTimeLimitBounds  = optionBounds{def: 15, min: 5, max: 60}
+	roundLimitBounds           = optionBounds{def: 3, min: 1, max: 10}
+	maxPlayersBounds           = optionBounds{def: 8, min: 2, max: 8} // One per player color.
+	startCountdownBounds       = optionBounds{def: 5, min: 1, max: 30}
+	reconnectGracePeriodBounds = optionBounds{def: 30, min: 5, max: 300}
+)
+
+// Scoring strategies selectable with GameOptions.Scoring.
+const (
+	// ScoringTimeLinear rewards guessers by how much of the turn was left.
+	ScoringTimeLinear = "timeLinear"
+	// ScoringOrderedBonus adds a bonus for the first three correct guesses.
+	ScoringOrderedBonus = "orderedBonus"
+	// ScoringDrawerShare gives the drawer a share of every correct guess.
+	ScoringDrawerShare = "drawerShare"
+	// ScoringHintPenalty lowers a guess's value as hint letters are revealed.
+	ScoringHintPenalty = "hintPenalty"
+)
+
+// Word difficulties, easiest first.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+var Difficulties = []string{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
+// defaultDifficultyMix offers one word of each difficulty.
+var defaultDifficultyMix = Difficulties
+
+// Word sources selectable with GameOptions.WordSource.
+const (
+	// WordSourceDefault only uses the shared word bank.
+	WordSourceDefault = "default"
+	// WordSourceCustom only uses the game's custom words.
+	WordSourceCustom = "custom"
+	// WordSourceMixed draws from the custom words and the word bank together.
+	WordSourceMixed = "mixed"
+)
+
+var wordSources = []string{WordSourceDefault, WordSourceCustom, WordSourceMixed}
+
+// Limits on custom word lists. A custom-only game needs enough words to
+// fill the choices offered to the drawer.
+const (
+	maxCustomWords      = 500
+	maxCustomWordLength = 32
+	minCustomOnlyWords  = 3
+)
+
+var scoringStrategies = []string{
+	ScoringTimeLinear,
+	ScoringOrderedBonus,
+	ScoringDrawerShare,
+	ScoringHintPenalty,
+}
+
+// OptionsError lists every invalid option by its JSON field name.
+type OptionsError struct {
+	Fields map[string]string `json:"fields"`
+}
+
+func (e *OptionsError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + ": " + e.Fields[name]
+	}
+	return "invalid game options: " + strings.Join(parts, ", ")
+}
+
+func DefaultGameOptions() GameOptions {
+	var o GameOptions
+	o.ApplyDefaults()
+	return o
+}
+
+// ApplyDefaults fills every option left at its zero value.
+func (o *GameOptions) ApplyDefaults() {
+	applyDefault(&o.TurnTimeLimit, turnTimeLimitBounds)
+	applyDefault(&o.WordSelectTimeLimit, wordSelectTimeLimitBounds)
+	applyDefault(&o.RoundLimit, roundLimitBounds)
+	applyDefault(&o.MaxPlayers, maxPlayersBounds)
+	applyDefault(&o.StartCountdown, startCountdownBounds)
+	applyDefault(&o.ReconnectGracePeriod, reconnectGracePeriodBounds)
+	if o.Scoring == "" {
+		o.Scoring = ScoringTimeLinear
+	}
+	if len(o.DifficultyMix) == 0 {
+		o.DifficultyMix = slices.Clone(defaultDifficultyMix)
+	}
+	o.CustomWords = CleanWordList(o.CustomWords)
+	if o.WordSource == "" {
+		o.WordSource = WordSourceDefault
+		if len(o.CustomWords) > 0 {
+			o.WordSource = WordSourceMixed
+		}
+	}
+}
+
+// Validate checks every option against its bounds. It returns an
+// *OptionsError describing all offending fields, or nil.
+func (o GameOptions) Validate() error {
+	fields := make(map[string]string)
+	checkBounds(fields, "turnTimeLimit", o.TurnTimeLimit, turnTimeLimitBounds)
+	checkBounds(fields, "wordSelectTimeLimit", o.WordSelectTimeLimit, wordSelectTimeLimitBounds)
+	checkBounds(fields, "roundLimit", o.RoundLimit, roundLimitBounds)
+	checkBounds(fields, "maxPlayers", o.MaxPlayers, maxPlayersBounds)
+	checkBounds(fields, "startCountdown", o.StartCountdown, startCountdownBounds)
+	checkBounds(fields, "reconnectGracePeriod", o.ReconnectGracePeriod, reconnectGracePeriodBounds)
+	if !slices.Contains(scoringStrategies, o.Scoring) {
+		fields["scoring"] = "must be one of " + strings.Join(scoringStrategies, ", ")
+	}
+	if len(o.DifficultyMix) == 0 {
+		fields["difficultyMix"] = "must list at least one difficulty"
+	}
+	for _, d := range o.DifficultyMix {
+		if !slices.Contains(Difficulties, d) {
+			fields["difficultyMix"] = "must only contain " + strings.Join(Difficulties, ", ")
+		}
+	}
+	if !slices.Contains(wordSources, o.WordSource) {
+		fields["wordSource"] = "must be one of " + strings.Join(wordSources, ", ")
+	} else if o.WordSource != WordSourceDefault && len(o.CustomWords) == 0 {
+		fields["customWords"] = "must not be empty when using custom words"
+	} else if o.WordSource == WordSourceCustom && len(o.CustomWords) < minCustomOnlyWords {
+		fields["customWords"] = fmt.Sprintf("must have at least %d words for a custom-only game", minCustomOnlyWords)
+	}
+	if len(o.CustomWords) > maxCustomWords {
+		fields["customWords"] = fmt.Sprintf("must have at most %d words", maxCustomWords)
+	}
+	for _, word := range o.CustomWords {
+		if msg := checkCustomWord(word); msg != "" {
+			fields["customWords"] = fmt.Sprintf("%q %s", word, msg)
+			break
+		}
+	}
+	for i, c := range o.Categories {
+		if strings.TrimSpace(c) == "" || slices.Contains(o.Categories[:i], c) {
+			fields["categories"] = "must be distinct category names"
+		}
+	}
+	if len(fields) > 0 {
+		return &OptionsError{Fields: fields}
+	}
+	return nil
+}
+
+func applyDefault(value *int, b optionBounds) {
+	if *value == 0 {
+		*value = b.def
+	}
+}
+
+func checkBounds(fields map[string]string, name string, value int, b optionBounds) {
+	if value < b.min || value > b.max {
+		fields[name] = fmt.Sprintf("must be between %d and %d", b.min, b.max)
+	}
+}
+
+// ParseWordList reads a word list pasted by a host. It accepts a JSON array
+// of strings, or plain text with one word per line or separated by commas.
+func ParseWordList(text string) ([]string, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "[") {
+		var words []string
+		if err := json.Unmarshal([]byte(text), &words); err != nil {
+			return nil, fmt.Errorf("invalid word list: %w", err)
+		}
+		return CleanWordList(words), nil
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ','
+	})
+	return CleanWordList(words), nil
+}
+
+// CleanWordList trims each word, collapses inner whitespace and drops blanks
+// and repeats. Repeats are matched ignoring case; the first spelling wins.
+func CleanWordList(words []string) []string {
+	if len(words) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(words))
+	cleaned := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.Join(strings.Fields(word), " ")
+		key := strings.ToLower(word)
+		if word == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, word)
+	}
+	return cleaned
+}
+
+// checkCustomWord describes what is wrong with a custom word, or returns ""
+// if it is fine. Words may hold letters, digits, spaces, hyphens and
+// apostrophes, and must contain at least one letter.
+func checkCustomWord(word string) string {
+	if utf8.RuneCountInString(word) > maxCustomWordLength {
+		return fmt.Sprintf("is longer than %d characters", maxCustomWordLength)
+	}
+	hasLetter := false
+	for _, r := range word {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r), r == ' ', r == '-', r == '\'':
+		default:
+			return fmt.Sprintf("contains %q; only letters, digits, spaces, hyphens and apostrophes are allowed", r)
+		}
+	}
+	if !hasLetter {
+		return "must contain a letter"
+	}
+	return ""
+}
@@ -1,10 +1,16 @@
 package shared
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type ClientInterface interface {
 	SendMessage([]byte) error
 	Close() error
+	// CloseWithReason sends a close frame with the given code before closing,
+	// so the client can tell why it was disconnected. It must not block.
+	CloseWithReason(code int, reason string) error
 	Write()
 	Read()
 }
@@ -22,6 +28,11 @@ type Player struct {
 	Connected      bool            `json:"connected"`
 	Avatar         string          `json:"avatar"`
 	Client         ClientInterface `json:"-"`
+	// IP is the address the player last joined or connected from.
+	IP string `json:"-"`
+	// ConnectedAt is when the current connection was established. It is zero
+	// while the player is disconnected.
+	ConnectedAt time.Time `json:"-"`
 }
 
 func (p *Player) String() string {
@@ -1,14 +1,68 @@
 package shared
 
+import "time"
+
 type GameOptions struct {
 	TurnTimeLimit       int `json:"turnTimeLimit"`
 	WordSelectTimeLimit int `json:"wordSelectTimeLimit"`
 	RoundLimit          int `json:"roundLimit"`
 	MaxPlayers          int `json:"maxPlayers"`
+	// StartCountdown is how many seconds the lobby counts down before the
+	// game starts.
+	StartCountdown int `json:"startCountdown"`
+	// ReconnectGracePeriod is how many seconds a disconnected player keeps
+	// their seat before being removed from the game.
+	ReconnectGracePeriod int `json:"reconnectGracePeriod"`
+	// Scoring names the strategy used to award points. See the Scoring*
+	// constants.
+	Scoring string `json:"scoring"`
+	// Categories limits the words to these categories. Empty means every
+	// category.
+	Categories []string `json:"categories,omitempty"`
+	// DifficultyMix lists the difficulty of each word offered to the drawer.
+	// The list repeats if it is shorter than the number of words offered.
+	DifficultyMix []string `json:"difficultyMix"`
+	// CustomWords is a word list supplied by the host for this game only.
+	CustomWords []string `json:"customWords,omitempty"`
+	// WordSource says whether words come from the word bank, the custom
+	// list or both. See the WordSource* constants.
+	WordSource string `json:"wordSource"`
 }
 
 type Word struct {
-	Id       uint   `gorm:"primaryKey" json:"id"`
-	Word     string `gorm:"not null" json:"word"`
-	Category string `gorm:"not null" json:"category"`
+	Id uint64 `gorm:"primaryKey" json:"id"`
+	// A word appears at most once per category, ignoring case.
+	Word     string `gorm:"not null;uniqueIndex:idx_words_word_category,collate:NOCASE" json:"word"`
+	Category string `gorm:"not null;index;uniqueIndex:idx_words_word_category" json:"category"`
+	// Difficulty is one of the Difficulty* constants.
+	Difficulty string `gorm:"not null;default:medium;index" json:"difficulty"`
+	// Aliases are other accepted answers, such as plurals or alternative
+	// spellings.
+	Aliases []string `gorm:"serializer:json" json:"aliases,omitempty"`
+	// Disabled words stay in the word bank but are never offered.
+	Disabled bool `gorm:"not null;default:false;index" json:"disabled"`
+}
+
+// WordPack is a named set of words imported together. Importing a pack again
+// replaces its words. Packs may share words.
+type WordPack struct {
+	Id         uint      `gorm:"primaryKey" json:"id"`
+	Name       string    `gorm:"not null;uniqueIndex" json:"name"`
+	Version    string    `json:"version"`
+	ImportedAt time.Time `json:"importedAt"`
+}
+
+// PackWord records that a word belongs to a word pack. Words added one at a
+// time belong to no pack.
+type PackWord struct {
+	PackID uint   `gorm:"primaryKey"`
+	WordID uint64 `gorm:"primaryKey;index"`
+}
+
+// WordCategory describes a category in the word bank.
+type WordCategory struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	// Difficulties counts the category's words at each difficulty.
+	Difficulties map[string]int `json:"difficulties"`
 }
@@ -0,0 +1,187 @@
+package wordpack
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format is a file format word packs are read and written in.
+type Format string
+
+const (
+	// FormatJSON is a Pack encoded as JSON. Decoding also accepts the legacy
+	// words.json layout, an object mapping each category to its words.
+	FormatJSON Format = "json"
+	// FormatCSV has a header row and one word per row. Only the word and
+	// category columns are required. Aliases are separated by "|". A CSV
+	// file does not record the pack's name or version.
+	FormatCSV Format = "csv"
+)
+
+// csvColumns are the columns written to CSV files, in order.
+var csvColumns = []string{"word", "category", "difficulty", "aliases", "disabled"}
+
+const aliasSeparator = "|"
+
+// ParseFormat returns the format named s.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatJSON, FormatCSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown word pack format %q", s)
+	}
+}
+
+// FormatOf returns the format of a file from its extension.
+func FormatOf(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// Decode reads a pack in the given format. The pack is not normalized.
+func Decode(r io.Reader, format Format) (Pack, error) {
+	switch format {
+	case FormatJSON:
+		return decodeJSON(r)
+	case FormatCSV:
+		return decodeCSV(r)
+	default:
+		return Pack{}, fmt.Errorf("unknown word pack format %q", format)
+	}
+}
+
+// Encode writes pack in the given format.
+func Encode(w io.Writer, format Format, pack Pack) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pack)
+	case FormatCSV:
+		return encodeCSV(w, pack)
+	default:
+		return fmt.Errorf("unknown word pack format %q", format)
+	}
+}
+
+func decodeJSON(r io.Reader) (Pack, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Pack{}, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Pack{}, fmt.Errorf("invalid JSON word pack: %w", err)
+	}
+
+	var pack Pack
+	if _, ok := fields["words"]; ok {
+		if err := json.Unmarshal(data, &pack); err != nil {
+			return Pack{}, fmt.Errorf("invalid JSON word pack: %w", err)
+		}
+		return pack, nil
+	}
+
+	var legacy map[string][]string
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return Pack{}, fmt.Errorf("invalid JSON word pack: %w", err)
+	}
+	categories := make([]string, 0, len(legacy))
+	for category := range legacy {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		for _, word := range legacy[category] {
+			pack.Words = append(pack.Words, Entry{Word: word, Category: category})
+		}
+	}
+	return pack, nil
+}
+
+func decodeCSV(r io.Reader) (Pack, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return Pack{}, errors.New("CSV word pack is empty")
+	}
+	if err != nil {
+		return Pack{}, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"word", "category"} {
+		if _, ok := columns[required]; !ok {
+			return Pack{}, fmt.Errorf("CSV word pack has no %q column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var pack Pack
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return pack, nil
+		}
+		if err != nil {
+			return Pack{}, err
+		}
+		entry := Entry{
+			Word:       field(record, "word"),
+			Category:   field(record, "category"),
+			Difficulty: field(record, "difficulty"),
+		}
+		if aliases := field(record, "aliases"); aliases != "" {
+			entry.Aliases = strings.Split(aliases, aliasSeparator)
+		}
+		if disabled := field(record, "disabled"); disabled != "" {
+			if entry.Disabled, err = strconv.ParseBool(disabled); err != nil {
+				line, _ := reader.FieldPos(0)
+				return Pack{}, fmt.Errorf("line %d: invalid disabled value %q", line, disabled)
+			}
+		}
+		pack.Words = append(pack.Words, entry)
+	}
+}
+
+func encodeCSV(w io.Writer, pack Pack) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, entry := range pack.Words {
+		disabled := ""
+		if entry.Disabled {
+			disabled = "true"
+		}
+		record := []string{
+			entry.Word,
+			entry.Category,
+			entry.Difficulty,
+			strings.Join(entry.Aliases, aliasSeparator),
+			disabled,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
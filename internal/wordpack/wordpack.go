@@ -0,0 +1,108 @@
+// Package wordpack reads and writes word packs: named, versioned word lists
+// that are imported into the word bank together.
+package wordpack
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+)
+
+// Pack is a word pack as stored in a file.
+type Pack struct {
+	Name    string  `json:"name"`
+	Version string  `json:"version,omitempty"`
+	Words   []Entry `json:"words"`
+}
+
+// Entry is a word in a pack.
+type Entry struct {
+	Word     string `json:"word"`
+	Category string `json:"category"`
+	// Difficulty is one of the shared.Difficulty* constants. It is estimated
+	// from the word when empty.
+	Difficulty string   `json:"difficulty,omitempty"`
+	Aliases    []string `json:"aliases,omitempty"`
+	// Disabled only applies to words the import adds. Importing a pack never
+	// re-enables or disables a word already in the word bank.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Normalize trims every field, fills in missing difficulties and checks that
+// the pack is well formed: every entry needs a word and a category, and a
+// word appears at most once per category, ignoring case.
+func (p *Pack) Normalize() error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Version = strings.TrimSpace(p.Version)
+	if p.Name == "" {
+		return fmt.Errorf("pack name is required")
+	}
+
+	seen := make(map[[2]string]bool, len(p.Words))
+	for i := range p.Words {
+		entry := &p.Words[i]
+		if err := entry.Normalize(); err != nil {
+			return fmt.Errorf("word %d: %w", i+1, err)
+		}
+		key := [2]string{strings.ToLower(entry.Word), entry.Category}
+		if seen[key] {
+			return fmt.Errorf("word %d: %q is repeated in category %q", i+1, entry.Word, entry.Category)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Normalize trims the entry's fields, estimates its difficulty if it has
+// none and checks that it is complete.
+func (e *Entry) Normalize() error {
+	e.Word = strings.Join(strings.Fields(e.Word), " ")
+	e.Category = strings.TrimSpace(e.Category)
+	e.Difficulty = strings.ToLower(strings.TrimSpace(e.Difficulty))
+	if e.Word == "" {
+		return fmt.Errorf("word is required")
+	}
+	if e.Category == "" {
+		return fmt.Errorf("category is required for %q", e.Word)
+	}
+	if e.Difficulty == "" {
+		e.Difficulty = EstimateDifficulty(e.Word)
+	}
+	if !slices.Contains(shared.Difficulties, e.Difficulty) {
+		return fmt.Errorf("difficulty of %q must be one of %s", e.Word, strings.Join(shared.Difficulties, ", "))
+	}
+
+	var aliases []string
+	for _, alias := range e.Aliases {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	e.Aliases = aliases
+	return nil
+}
+
+// EstimateDifficulty guesses how hard a word is to draw from its length, for
+// word lists that do not record difficulties. Phrases count as hard.
+func EstimateDifficulty(word string) string {
+	if strings.ContainsRune(strings.TrimSpace(word), ' ') {
+		return shared.DifficultyHard
+	}
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	switch {
+	case letters <= 5:
+		return shared.DifficultyEasy
+	case letters <= 8:
+		return shared.DifficultyMedium
+	default:
+		return shared.DifficultyHard
+	}
+}
@@ -0,0 +1,95 @@
+package wordpack_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/Ajstraight619/pictionary-server/internal/wordpack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pack = wordpack.Pack{
+	Name:    "office",
+	Version: "2024.1",
+	Words: []wordpack.Entry{
+		{Word: "Stapler", Category: "Office", Difficulty: shared.DifficultyMedium},
+		{Word: "Coffee mug", Category: "Office", Difficulty: shared.DifficultyHard, Aliases: []string{"mug", "cup"}},
+		{Word: "Fax", Category: "Retro", Difficulty: shared.DifficultyEasy, Disabled: true},
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []wordpack.Format{wordpack.FormatJSON, wordpack.FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, wordpack.Encode(&buf, format, pack))
+
+			decoded, err := wordpack.Decode(&buf, format)
+			require.NoError(t, err)
+			if format == wordpack.FormatCSV {
+				// CSV files only hold the words.
+				decoded.Name, decoded.Version = pack.Name, pack.Version
+			}
+			assert.Equal(t, pack, decoded)
+		})
+	}
+}
+
+func TestDecodeLegacyJSON(t *testing.T) {
+	decoded, err := wordpack.Decode(strings.NewReader(`{"Sports": ["Tennis"], "Animals": ["Ants", "Albatross"]}`), wordpack.FormatJSON)
+	require.NoError(t, err)
+	decoded.Name = "default"
+	require.NoError(t, decoded.Normalize())
+
+	assert.Equal(t, []wordpack.Entry{
+		{Word: "Ants", Category: "Animals", Difficulty: shared.DifficultyEasy},
+		{Word: "Albatross", Category: "Animals", Difficulty: shared.DifficultyHard},
+		{Word: "Tennis", Category: "Sports", Difficulty: shared.DifficultyMedium},
+	}, decoded.Words)
+}
+
+func TestDecodeCSV(t *testing.T) {
+	csv := "Category,Word,Notes\nAnimals, Koala ,cute\nAnimals,Ants,\n"
+	decoded, err := wordpack.Decode(strings.NewReader(csv), wordpack.FormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, []wordpack.Entry{
+		{Word: "Koala", Category: "Animals"},
+		{Word: "Ants", Category: "Animals"},
+	}, decoded.Words)
+
+	_, err = wordpack.Decode(strings.NewReader("word\nKoala\n"), wordpack.FormatCSV)
+	assert.ErrorContains(t, err, "category")
+
+	_, err = wordpack.Decode(strings.NewReader("word,category,disabled\nKoala,Animals,maybe\n"), wordpack.FormatCSV)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestNormalize(t *testing.T) {
+	p := wordpack.Pack{Name: " office ", Words: []wordpack.Entry{
+		{Word: "  Coffee   mug ", Category: " Office ", Difficulty: "EASY", Aliases: []string{" mug ", ""}},
+	}}
+	require.NoError(t, p.Normalize())
+	assert.Equal(t, "office", p.Name)
+	assert.Equal(t, wordpack.Entry{Word: "Coffee mug", Category: "Office", Difficulty: shared.DifficultyEasy, Aliases: []string{"mug"}}, p.Words[0])
+
+	for name, entry := range map[string]wordpack.Entry{
+		"no word":            {Category: "Office"},
+		"no category":        {Word: "Stapler"},
+		"unknown difficulty": {Word: "Stapler", Category: "Office", Difficulty: "extreme"},
+	} {
+		p := wordpack.Pack{Name: "office", Words: []wordpack.Entry{entry}}
+		assert.Error(t, p.Normalize(), name)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	f, err := wordpack.FormatOf("packs/office.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, wordpack.FormatCSV, f)
+
+	_, err = wordpack.FormatOf("packs/office.txt")
+	assert.Error(t, err)
+}
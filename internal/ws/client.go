@@ -3,10 +3,13 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
 	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/protocol"
+	"github.com/Ajstraight619/pictionary-server/internal/utils"
 	"github.com/gorilla/websocket"
 )
 
@@ -33,26 +36,39 @@ type Client struct {
 	Send     chan []byte
 	Conn     *websocket.Conn
 	PlayerID string
-	ctx      context.Context
-	cancel   context.CancelFunc
+	// Version is the protocol version negotiated for the connection.
+	// Messages on Send are encoded for protocol.Current and downgraded as
+	// they are written.
+	Version protocol.Version
+	// closeFrame carries a close frame for Write to send before it shuts
+	// the connection.
+	closeFrame chan []byte
+	ctx        context.Context
+	cancel     context.CancelFunc
 }
 
-func NewClient(hub *Hub, conn *websocket.Conn, playerID string) *Client {
+func NewClient(hub *Hub, conn *websocket.Conn, playerID string, version protocol.Version) *Client {
 	ctx, cancel := context.WithCancel(hub.ctx)
 	return &Client{
-		Hub:      hub,
-		Send:     make(chan []byte, 256),
-		Conn:     conn,
-		PlayerID: playerID,
-		ctx:      ctx,
-		cancel:   cancel,
+		Hub:        hub,
+		Send:       make(chan []byte, 256),
+		Conn:       conn,
+		PlayerID:   playerID,
+		Version:    version,
+		closeFrame: make(chan []byte, 1),
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 }
 
 func (c *Client) Read() {
 	defer func() {
 		log.Printf("Client.Read: unregistering and closing connection for player %s", c.PlayerID)
-		c.Hub.Unregister <- c
+		select {
+		case c.Hub.Unregister <- c:
+		case <-c.Hub.ctx.Done():
+			// The hub has stopped and closes every client itself.
+		}
 		c.cancel()
 		c.Conn.Close()
 	}()
@@ -64,11 +80,23 @@ func (c *Client) Read() {
 	})
 
 	recognizedEvents := map[string]bool{
-		e.GameState:   true,
-		e.PlayerGuess: true,
-		e.StartTimer:  true,
-		e.StopTimer:   true,
-		e.SelectWord:  true,
+		e.GameState:     true,
+		e.PlayerGuess:   true,
+		e.Chat:          true,
+		e.StartTimer:    true,
+		e.StopTimer:     true,
+		e.SelectWord:    true,
+		e.RerollWords:   true,
+		e.StrokeBegin:   true,
+		e.StrokePoints:  true,
+		e.StrokeEnd:     true,
+		e.Fill:          true,
+		e.Undo:          true,
+		e.ClearCanvas:   true,
+		e.TransferHost:  true,
+		e.KickPlayer:    true,
+		e.BanPlayer:     true,
+		e.UpdateOptions: true,
 	}
 
 	for {
@@ -79,28 +107,48 @@ func (c *Client) Read() {
 		}
 
 		var gameEvent e.GameEvent
-		// Try to parse the message as a GameEvent.
-		if err := json.Unmarshal(message, &gameEvent); err == nil && gameEvent.Type != "" {
-			// Only handle if it's a recognized event
-			if recognizedEvents[gameEvent.Type] {
-				select {
-				case <-c.ctx.Done():
-					log.Printf("Client.Read: context cancelled for player %s", c.PlayerID)
-					return
-				case c.Hub.GameEvents <- gameEvent:
-					log.Printf("Client.Read: Dispatched game event %s for player %s", gameEvent.Type, c.PlayerID)
-					continue // Skip broadcasting; it is handled internally.
-				default:
-					log.Printf("Client.Read: GameEvents channel full, discarding event for player %s", c.PlayerID)
-				}
-			}
+		if err := json.Unmarshal(message, &gameEvent); err != nil {
+			log.Printf("Client.Read: dropping malformed message from player %s", c.PlayerID)
+			c.sendError(gameEvent, e.CodeInvalidPayload, "Malformed message")
+			continue
+		}
+		if !recognizedEvents[gameEvent.Type] {
+			log.Printf("Client.Read: dropping unrecognized message from player %s", c.PlayerID)
+			c.sendError(gameEvent, e.CodeUnknownEvent, fmt.Sprintf("Unknown event %q", gameEvent.Type))
+			continue
 		}
+		gameEvent.PlayerID = c.PlayerID
 
-		c.Hub.Broadcast <- message
+		select {
+		case <-c.ctx.Done():
+			log.Printf("Client.Read: context cancelled for player %s", c.PlayerID)
+			return
+		case c.Hub.GameEvents <- gameEvent:
+			log.Printf("Client.Read: Dispatched game event %s for player %s", gameEvent.Type, c.PlayerID)
+		default:
+			log.Printf("Client.Read: GameEvents channel full, discarding event for player %s", c.PlayerID)
+			c.sendError(gameEvent, e.CodeBackpressure, "The game is busy, try again")
+		}
 	}
 
 }
 
+// sendError tells this client that event was dropped before reaching the
+// game.
+func (c *Client) sendError(event e.GameEvent, code, message string) {
+	b, err := utils.CreateMessage(e.Error, e.ErrorPayload{
+		Event:     event.Type,
+		RequestID: event.RequestID,
+		Code:      code,
+		Message:   message,
+	})
+	if err != nil {
+		log.Println("error marshalling error message:", err)
+		return
+	}
+	c.Hub.SendToPlayer(c.PlayerID, b)
+}
+
 func (c *Client) Write() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
@@ -112,6 +160,12 @@ func (c *Client) Write() {
 		select {
 		case <-c.ctx.Done():
 			log.Printf("Client.Write: context cancelled for player %s", c.PlayerID)
+			c.flush()
+			return
+		case frame := <-c.closeFrame:
+			if err := c.Conn.WriteControl(websocket.CloseMessage, frame, time.Now().Add(writeWait)); err != nil {
+				log.Printf("Client.Write: error sending close frame to player %s: %v", c.PlayerID, err)
+			}
 			return
 		case message, ok := <-c.Send:
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
@@ -120,6 +174,10 @@ func (c *Client) Write() {
 				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
+			message, ok = protocol.Downgrade(c.Version, message)
+			if !ok {
+				continue
+			}
 			w, err := c.Conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				log.Printf("Client.Write: NextWriter error for player %s: %v", c.PlayerID, err)
@@ -132,8 +190,11 @@ func (c *Client) Write() {
 			// Drain queued messages.
 			n := len(c.Send)
 			for range n {
+				additional, ok := protocol.Downgrade(c.Version, <-c.Send)
+				if !ok {
+					continue
+				}
 				w.Write(newline)
-				additional := <-c.Send
 				if _, err := w.Write(additional); err != nil {
 					log.Printf("Client.Write: error writing queued message for player %s: %v", c.PlayerID, err)
 					return
@@ -153,6 +214,25 @@ func (c *Client) Write() {
 	}
 }
 
+// flush writes every message still queued once the client is shutting down,
+// such as the final results of a game that just ended, and then closes the
+// connection cleanly. The hub closes Send once nothing more can arrive.
+func (c *Client) flush() {
+	for message := range c.Send {
+		message, ok := protocol.Downgrade(c.Version, message)
+		if !ok {
+			continue
+		}
+		c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+		if err := c.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			log.Printf("Client.Write: flush error for player %s: %v", c.PlayerID, err)
+			return
+		}
+	}
+	c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+	c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+}
+
 func (c *Client) SendMessage(data []byte) error {
 	return c.Conn.WriteMessage(websocket.TextMessage, data)
 }
@@ -160,3 +240,14 @@ func (c *Client) SendMessage(data []byte) error {
 func (c *Client) Close() error {
 	return c.Conn.Close()
 }
+
+// CloseWithReason hands a close frame to Write, which sends it and then closes
+// the connection. It does not wait for the write, so it is safe to call from
+// the game loop. Only the first call has any effect.
+func (c *Client) CloseWithReason(code int, reason string) error {
+	select {
+	case c.closeFrame <- websocket.FormatCloseMessage(code, reason):
+	default:
+	}
+	return nil
+}
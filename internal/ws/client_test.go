@@ -0,0 +1,150 @@
+package ws_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ajstraight619/pictionary-server/internal/clock"
+	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	g "github.com/Ajstraight619/pictionary-server/internal/game"
+	"github.com/Ajstraight619/pictionary-server/internal/game/gametest"
+	"github.com/Ajstraight619/pictionary-server/internal/protocol"
+	"github.com/Ajstraight619/pictionary-server/internal/shared"
+	"github.com/Ajstraight619/pictionary-server/internal/ws"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// stopOnEnd tears the game down as soon as it ends, like the game server.
+type stopOnEnd struct {
+	cancel context.CancelFunc
+}
+
+func (s stopOnEnd) OnGameEnded(string) {
+	s.cancel()
+}
+
+func TestFinalResultsReachClients(t *testing.T) {
+	require.NoError(t, gametest.UseMemoryDB(gametest.DefaultWords))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := clock.NewFake(time.Unix(0, 0))
+	hub := ws.NewHub(ctx)
+	options := shared.GameOptions{
+		MaxPlayers:          2,
+		TurnTimeLimit:       2,
+		RoundLimit:          1,
+		WordSelectTimeLimit: 1,
+		StartCountdown:      1,
+	}
+	game := g.NewGame(ctx, "game", options, hub, stopOnEnd{cancel}, clk)
+	game.InitGameEvents()
+	go hub.Run()
+	go game.Run()
+
+	for _, id := range []string{"host", "guest"} {
+		player := game.NewPlayer(id, id, id == "host")
+		player.Pending = true
+		require.NoError(t, game.AddPlayer(player))
+	}
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := ws.NewClient(hub, conn, "host", protocol.Current)
+		game.ConnectPlayer("host", "", client)
+		hub.Register <- client
+		go client.Write()
+		go client.Read()
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	received := make(chan []string)
+	go func() {
+		var types []string
+		defer func() { received <- types }()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			for _, line := range bytes.Split(data, []byte{'\n'}) {
+				var msg struct {
+					Type string `json:"type"`
+				}
+				if json.Unmarshal(line, &msg) == nil {
+					types = append(types, msg.Type)
+				}
+			}
+		}
+	}()
+
+	start, err := json.Marshal(e.StartTimerPayload{TimerType: "startGameCountdown"})
+	require.NoError(t, err)
+	hub.GameEvents <- e.GameEvent{Type: e.StartTimer, Payload: start, PlayerID: "host"}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for ctx.Err() == nil {
+		require.True(t, time.Now().Before(deadline), "game did not end")
+		clk.Advance(time.Second)
+		time.Sleep(time.Millisecond)
+	}
+
+	types := <-received
+	assert.Contains(t, types, e.Podium)
+	require.NotEmpty(t, types)
+	assert.Equal(t, e.GameEnded, types[len(types)-1])
+}
+
+func TestCancelWhileRunning(t *testing.T) {
+	require.NoError(t, gametest.UseMemoryDB(gametest.DefaultWords))
+
+	for range 20 {
+		ctx, cancel := context.WithCancel(context.Background())
+		hub := ws.NewHub(ctx)
+		game := g.NewGame(ctx, "game", shared.DefaultGameOptions(), hub, nil, clock.NewFake(time.Unix(0, 0)))
+		game.InitGameEvents()
+
+		hubDone, gameDone := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(hubDone)
+			hub.Run()
+		}()
+		go func() {
+			defer close(gameDone)
+			game.Run()
+		}()
+		game.Sync()
+
+		// The game says goodbye while the hub is shutting down, and may keep
+		// broadcasting, or hear from a client, after the hub has stopped.
+		cancel()
+		hub.BroadcastMessage([]byte("late"))
+		<-hubDone
+		hub.BroadcastMessage([]byte("later"))
+		hub.GameEvents <- e.GameEvent{Type: e.GameState, PlayerID: "host"}
+		<-gameDone
+	}
+}
@@ -6,16 +6,24 @@ import (
 	"sync"
 
 	e "github.com/Ajstraight619/pictionary-server/internal/events"
+	"github.com/Ajstraight619/pictionary-server/internal/messaging"
 )
 
 type Hub struct {
-	ctx          context.Context
-	Broadcast    chan []byte
-	GameEvents   chan e.GameEvent
-	Clients      map[*Client]bool
-	Register     chan *Client
-	Unregister   chan *Client
-	OnDisconnect func(playerID string)
+	ctx         context.Context
+	Broadcast   chan []byte
+	GameEvents  chan e.GameEvent
+	Disconnects chan messaging.Disconnect
+	Clients     map[*Client]bool
+	Register    chan *Client
+	Unregister  chan *Client
+	direct      chan directMessage
+}
+
+// directMessage is a message for a single player.
+type directMessage struct {
+	playerID string
+	message  []byte
 }
 
 type Hubs struct {
@@ -25,12 +33,14 @@ type Hubs struct {
 
 func NewHub(ctx context.Context) *Hub {
 	return &Hub{
-		ctx:        ctx,
-		Broadcast:  make(chan []byte),
-		GameEvents: make(chan e.GameEvent, 10),
-		Clients:    make(map[*Client]bool),
-		Register:   make(chan *Client),
-		Unregister: make(chan *Client),
+		ctx:         ctx,
+		Broadcast:   make(chan []byte),
+		GameEvents:  make(chan e.GameEvent, 64), // Drawing events arrive in bursts.
+		Disconnects: make(chan messaging.Disconnect, 16),
+		Clients:     make(map[*Client]bool),
+		Register:    make(chan *Client),
+		Unregister:  make(chan *Client),
+		direct:      make(chan directMessage),
 	}
 }
 
@@ -65,22 +75,26 @@ func (h *Hub) Run() {
 	for {
 		select {
 		case client := <-h.Register:
+			// A reconnecting player replaces their previous connection.
+			for existing := range h.Clients {
+				if existing.PlayerID == client.PlayerID {
+					delete(h.Clients, existing)
+					close(existing.Send)
+				}
+			}
 			h.Clients[client] = true
 		case client := <-h.Unregister:
 			if _, ok := h.Clients[client]; ok {
-				delete(h.Clients, client)
-				close(client.Send)
-				// if h.OnDisconnect != nil {
-				// 	go h.OnDisconnect(client.PlayerID)
-				// }
+				h.removeClient(client)
 			}
 		case message := <-h.Broadcast:
 			for client := range h.Clients {
-				select {
-				case client.Send <- message:
-				default:
-					close(client.Send)
-					delete(h.Clients, client)
+				h.deliver(client, message)
+			}
+		case direct := <-h.direct:
+			for client := range h.Clients {
+				if client.PlayerID == direct.playerID {
+					h.deliver(client, direct.message)
 				}
 			}
 		case <-h.ctx.Done():
@@ -90,16 +104,52 @@ func (h *Hub) Run() {
 	}
 }
 
+// deliver queues message for client, dropping the client if it has fallen
+// too far behind.
+func (h *Hub) deliver(client *Client, message []byte) {
+	select {
+	case client.Send <- message:
+	default:
+		h.removeClient(client)
+	}
+}
+
+// removeClient drops a client and, unless the player already has a newer
+// connection, reports the player as disconnected.
+func (h *Hub) removeClient(client *Client) {
+	delete(h.Clients, client)
+	close(client.Send)
+
+	for other := range h.Clients {
+		if other.PlayerID == client.PlayerID {
+			return
+		}
+	}
+	// Never block the hub on the game loop, which may itself be waiting to
+	// broadcast.
+	go func() {
+		select {
+		case h.Disconnects <- messaging.Disconnect{PlayerID: client.PlayerID, Client: client}:
+		case <-h.ctx.Done():
+		}
+	}()
+}
+
+// BroadcastMessage queues message for every connection. Messages sent after
+// the hub has shut down are dropped.
 func (h *Hub) BroadcastMessage(message []byte) {
-	h.Broadcast <- message
+	select {
+	case h.Broadcast <- message:
+	case <-h.ctx.Done():
+	}
 }
 
+// SendToPlayer queues message for playerID's connection. Clients are only
+// touched on the Run goroutine, so it is safe to call from anywhere.
 func (h *Hub) SendToPlayer(playerID string, message []byte) {
-	for client := range h.Clients {
-		if client.PlayerID == playerID {
-			client.Send <- message
-			break
-		}
+	select {
+	case h.direct <- directMessage{playerID: playerID, message: message}:
+	case <-h.ctx.Done():
 	}
 }
 
@@ -107,15 +157,17 @@ func (h *Hub) GameEventChannel() <-chan e.GameEvent {
 	return h.GameEvents
 }
 
+func (h *Hub) DisconnectChannel() <-chan messaging.Disconnect {
+	return h.Disconnects
+}
+
 func (h *Hub) cleanup() {
-	// Close all client connections
+	// Closing Send lets each client write what is still queued, such as the
+	// final results, before it closes its connection.
 	for client := range h.Clients {
 		close(client.Send)
-		client.Conn.Close()
 		delete(h.Clients, client)
 	}
-
-	// Clean up channels
-	close(h.Broadcast)
-	close(h.GameEvents)
+	// Broadcast and GameEvents are left open: the game and clients may still
+	// send on them, and nothing waits for them to be closed.
 }